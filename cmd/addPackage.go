@@ -7,12 +7,13 @@ import (
 	"strconv"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/Arapak/sio-tool/polygon_package"
 	"github.com/Arapak/sio-tool/util"
 	"github.com/fatih/color"
 	"github.com/otiai10/copy"
 )
 
-const ErrorFileIsNotADirectory = "this file is not a directory"
+const ErrorFileIsNotADirectory = "this file is neither a directory nor an archive (.zip, .tgz, .tar.gz, .tar)"
 
 func AddPackage() (err error) {
 	fileInfo, err := os.Stat(Args.File)
@@ -25,25 +26,70 @@ func AddPackage() (err error) {
 	}
 	destination = getPackageNumber(destination)
 
-	if !fileInfo.IsDir() {
+	archive := !fileInfo.IsDir() && util.IsArchive(Args.File)
+	if !fileInfo.IsDir() && !archive {
 		return errors.New(ErrorFileIsNotADirectory)
 	}
-	color.Green("Coping package to destination: %v", destination)
-	err = copy.Copy(Args.File, destination)
-	if err != nil {
-		return
+	if archive {
+		color.Green("Extracting package to destination: %v", destination)
+		if err = extractPackage(Args.File, destination); err != nil {
+			_ = os.RemoveAll(destination)
+			return
+		}
+		color.Green("Successfully extracted package")
+	} else {
+		color.Green("Coping package to destination: %v", destination)
+		err = copy.Copy(Args.File, destination)
+		if err != nil {
+			return
+		}
+		color.Green("Successfully copied package")
 	}
-	color.Green("Successfully copied package")
-	deleteFolder := true
-	if err = survey.AskOne(&survey.Confirm{Message: `Do you want to delete the original folder?`, Default: true}, &deleteFolder); err != nil {
+	printPackageTests(destination)
+
+	message := `Do you want to delete the original folder?`
+	if archive {
+		message = `Do you want to delete the original archive?`
+	}
+	deleteOriginal := true
+	if err = survey.AskOne(&survey.Confirm{Message: message, Default: true}, &deleteOriginal); err != nil {
 		return
 	}
-	if deleteFolder {
+	if deleteOriginal {
 		return os.RemoveAll(Args.File)
 	}
 	return
 }
 
+// extractPackage extracts the archive, without the folder holding the whole package if there is one
+func extractPackage(archive, destination string) (err error) {
+	if err = util.ExtractArchive(archive, destination); err != nil {
+		return
+	}
+	return util.StripSingleDir(destination)
+}
+
+// printPackageTests shows how the tests of the package were found
+func printPackageTests(packagePath string) {
+	polygon, err := polygon_package.Load(packagePath)
+	if err != nil {
+		color.Yellow(err.Error())
+	} else if polygon != nil {
+		testset := polygon.Testset()
+		color.Green("Found a Polygon package of %v (%v tests, %vms, %vMB)", polygon.Name(), len(testset.Tests), testset.TimeLimit, testset.MemoryLimit/1024/1024)
+	}
+	in, out, err := getAllTests(packagePath)
+	if err != nil && polygon != nil {
+		color.Yellow("Cannot find the tests in the package, st package_test will generate them from package.xml")
+		return
+	}
+	if err != nil {
+		color.Yellow("Cannot find the tests in the package, st package_test won't be able to use it")
+		return
+	}
+	color.Green("Found %v tests (e.g. %v and %v)", len(in), in[0], out[0])
+}
+
 func getPackageNumber(path string) (packagePath string) {
 	i := 0
 	for {
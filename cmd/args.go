@@ -22,6 +22,10 @@ type ParsedArgs struct {
 	Generator        string
 	Solve            string
 	Brute            string
+	Checker          string
+	Interactor       string
+	Compare          string
+	Profile          string
 	Source           string
 	Name             string
 	Path             string
@@ -31,8 +35,29 @@ type ParsedArgs struct {
 	Stage            string
 	TimeLimit        string   `docopt:"--time_limit"`
 	MemoryLimit      string   `docopt:"--memory_limit"`
+	SideBySide       bool     `docopt:"--side-by-side"`
+	Report           string   `docopt:"--report"`
+	ReportFile       string   `docopt:"--report_file"`
+	DebugFailing     bool     `docopt:"--debug_failing"`
+	Stderr           bool     `docopt:"--stderr"`
+	SeedStart        string   `docopt:"--seed-start"`
+	Iterations       string   `docopt:"--iterations"`
+	Duration         string   `docopt:"--duration"`
+	Workers          string   `docopt:"--workers"`
+	SeedArg          bool     `docopt:"--seed-arg"`
+	NoShrink         bool     `docopt:"--no-shrink"`
+	Validator        string   `docopt:"--validator"`
+	Maximize         string   `docopt:"--maximize"`
+	Top              string   `docopt:"--top"`
+	StopOnLimit      bool     `docopt:"--stop-on-limit"`
+	AllSolutions     bool     `docopt:"--all"`
+	Official         bool     `docopt:"--official"`
+	Upload           bool     `docopt:"--upload"`
 	Specifier        []string `docopt:"<specifier>"`
+	Solutions        []string `docopt:"<solutions>"`
 	Alias            string   `docopt:"<alias>"`
+	Run              string   `docopt:"<run>"`
+	OtherRun         string   `docopt:"<other_run>"`
 	Accepted         bool     `docopt:"ac"`
 	All              bool     `docopt:"all"`
 	Handle           string   `docopt:"<handle>"`
@@ -44,9 +69,11 @@ type ParsedArgs struct {
 	Gen              bool     `docopt:"gen"`
 	Test             bool     `docopt:"test"`
 	PackageTest      bool     `docopt:"package_test"`
+	MakeTests        bool     `docopt:"make-tests"`
 	AddPackage       bool     `docopt:"add_package"`
 	DownloadPackages bool     `docopt:"download_packages"`
 	UploadPackage    bool     `docopt:"upload_package"`
+	ExportPackage    bool     `docopt:"export-package"`
 	Watch            bool     `docopt:"watch"`
 	Open             bool     `docopt:"open"`
 	Stand            bool     `docopt:"stand"`
@@ -60,12 +87,16 @@ type ParsedArgs struct {
 	Add              bool     `docopt:"add"`
 	Find             bool     `docopt:"find"`
 	Goto             bool     `docopt:"goto"`
+	History          bool     `docopt:"history"`
+	Diff             bool     `docopt:"diff"`
 	Codeforces       bool
 	Szkopul          bool
 	SioStaszic       bool
 	SioMimuw         bool
 	SioTalent        bool
 	Oiejq            bool
+	Cgroup           bool
+	Isolate          bool
 	Verbose          bool
 }
 
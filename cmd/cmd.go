@@ -37,8 +37,17 @@ func Eval(opts docopt.Opts) error {
 		return Upgrade()
 	} else if Args.PackageTest {
 		return PackageTest()
+	} else if Args.MakeTests {
+		return MakeTests()
 	} else if Args.AddPackage {
 		return AddPackage()
+	} else if Args.ExportPackage {
+		return ExportPackage()
+	} else if Args.History {
+		if Args.Diff {
+			return HistoryDiff()
+		}
+		return History()
 	} else if Args.Database {
 		if Args.Add {
 			return DatabaseAdd()
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Arapak/sio-tool/codeforces_client"
 	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/metadata"
 )
 
 func CodeforcesParse() (err error) {
@@ -41,10 +42,25 @@ func CodeforcesParse() (err error) {
 	defer db.Close()
 
 	work := func() error {
-		_, paths, err := cln.Parse(info, db)
+		parsed, paths, err := cln.Parse(info, db)
 		if err != nil {
 			return err
 		}
+		for _, problem := range parsed {
+			m := &metadata.Problem{
+				Name:                   problem.Name,
+				Link:                   problem.Link,
+				Codeforces:             &problem.Info,
+				TimeLimitInSeconds:     problem.Limits.TimeLimitInSeconds,
+				MemoryLimitInMegabytes: problem.Limits.MemoryLimitInMegabytes,
+				IO:                     metadata.IOMode(problem.StandardIO),
+				InputFile:              problem.InputFile,
+				OutputFile:             problem.OutputFile,
+			}
+			if err := m.Update(problem.Path); err != nil {
+				color.Red(err.Error())
+			}
+		}
 		if cfg.GenAfterParse {
 			for _, path := range paths {
 				err := GenFiles(source, path, ext)
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Arapak/sio-tool/util"
+	"github.com/fatih/color"
+	"github.com/mitchellh/go-homedir"
+)
+
+const compileCachePath = "~/.st/cache"
+
+var compilerVersions = map[string]string{}
+
+func compilerVersion(compiler string) string {
+	if version, ok := compilerVersions[compiler]; ok {
+		return version
+	}
+	version, _ := exec.Command(compiler, "--version").CombinedOutput()
+	compilerVersions[compiler] = string(version)
+	return string(version)
+}
+
+// artifact returns the file run by the script, if the program has one. Only a path inside the program's
+// folder is taken for it, the script of an interpreted language starts with the interpreter.
+func (p *program) artifact() string {
+	cmds := util.SplitCmd(p.command())
+	if len(cmds) == 0 || !strings.ContainsAny(cmds[0], `/\`) {
+		return ""
+	}
+	artifact := filepath.Clean(cmds[0])
+	if artifact == filepath.Clean(filepath.Join(p.path, p.full)) {
+		return ""
+	}
+	dir, err := filepath.Abs(filepath.Join(p.path, "."))
+	if err != nil {
+		return ""
+	}
+	path, err := filepath.Abs(artifact)
+	if err != nil {
+		return ""
+	}
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return artifact
+}
+
+// cacheKey hashes everything the compiled program depends on: the source, the scripts and the compiler version.
+// The unfiltered scripts are used, so that $%rand%$ doesn't change the key.
+func (p *program) cacheKey() (key string, err error) {
+	source, err := os.ReadFile(filepath.Join(p.path, p.full))
+	if err != nil {
+		return
+	}
+	cmds := util.SplitCmd(p.template.BeforeScript)
+	if len(cmds) == 0 {
+		return
+	}
+	hash := sha256.New()
+	for _, part := range []string{string(source), p.template.BeforeScript, p.template.Script, p.full, compilerVersion(cmds[0])} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func cachedArtifactPath(key string) (string, error) {
+	path, err := homedir.Expand(compileCachePath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(path, key), nil
+}
+
+func copyExecutable(src, dst string) (err error) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return
+	}
+	tmp := dst + ".tmp-" + util.RandString(8)
+	if err = os.WriteFile(tmp, data, 0755); err != nil {
+		return
+	}
+	return os.Rename(tmp, dst)
+}
+
+// restoreFromCache puts the cached artifact in place, reporting whether it was found
+func (p *program) restoreFromCache(key string) bool {
+	artifact := p.artifact()
+	cached, err := cachedArtifactPath(key)
+	if err != nil || artifact == "" || !util.FileExists(cached) {
+		return false
+	}
+	if err = copyExecutable(cached, artifact); err != nil {
+		color.Red("cannot restore %v from the compilation cache: %v", artifact, err.Error())
+		return false
+	}
+	return true
+}
+
+func (p *program) saveToCache(key string) {
+	artifact := p.artifact()
+	if artifact == "" || !util.FileExists(artifact) {
+		return
+	}
+	cached, err := cachedArtifactPath(key)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(cached), 0755); err == nil {
+		err = copyExecutable(artifact, cached)
+	}
+	if err != nil {
+		color.Red("cannot save %v to the compilation cache: %v", artifact, err.Error())
+	}
+}
@@ -24,14 +24,16 @@ func Config() (err error) {
 			`add a template`,
 			`delete a template`,
 			`set default template`,
+			`add a build profile to a template`,
 			`run "st gen" after "st parse"`,
 			`set host domains`,
 			`set proxy`,
 			`set folders' name`,
 			`set default naming`,
 			`set database path`,
+			`set default judging backend`,
 		},
-		PageSize: 10,
+		PageSize: 12,
 	}
 	if err = survey.AskOne(prompt, &index); err != nil {
 		return
@@ -68,17 +70,21 @@ func Config() (err error) {
 	} else if index == 3 {
 		return cfg.SetDefaultTemplate()
 	} else if index == 4 {
-		return cfg.SetGenAfterParse()
+		return cfg.AddProfile()
 	} else if index == 5 {
-		return cfg.SetHost()
+		return cfg.SetGenAfterParse()
 	} else if index == 6 {
-		return cfg.SetProxy()
+		return cfg.SetHost()
 	} else if index == 7 {
-		return cfg.SetFolderName()
+		return cfg.SetProxy()
 	} else if index == 8 {
-		return cfg.SetDefaultNaming()
+		return cfg.SetFolderName()
 	} else if index == 9 {
+		return cfg.SetDefaultNaming()
+	} else if index == 10 {
 		return cfg.SetDbPath()
+	} else if index == 11 {
+		return cfg.SetBackend()
 	}
 	return
 }
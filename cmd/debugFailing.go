@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/Arapak/sio-tool/config"
+
+	"github.com/fatih/color"
+)
+
+// rerunWithDebugProfile runs the solution built with the debug profile on the failing test
+// and shows what it printed to stderr, where the sanitizers print their reports
+func rerunWithDebugProfile(solve *program, testID string, input []byte) (err error) {
+	debug := newProgram(filepath.Join(solve.path, solve.full), solve.base, solve.task)
+	if err = debug.useProfile(config.DebugProfile); err != nil {
+		return
+	}
+	color.Cyan("Re-running test %v with the %v profile", testID, config.DebugProfile)
+	if err = debug.compile(); err != nil {
+		return
+	}
+	options, err := runOptions()
+	if err != nil {
+		return
+	}
+	if err = debug.applyProfile(options); err != nil {
+		return
+	}
+	processInfo, err := options.Run(debug.command(), bytes.NewReader(input))
+	if err != nil {
+		color.Red("#%v %v: %v", testID, processInfo.Status, err.Error())
+	} else {
+		color.Yellow("#%v %v", testID, processInfo.Status)
+	}
+	if len(processInfo.Stderr) == 0 {
+		color.Cyan("Nothing was printed to stderr")
+	} else {
+		color.Cyan("-----Stderr-----")
+		_, _ = os.Stdout.Write(processInfo.Stderr)
+	}
+	return debug.clean()
+}
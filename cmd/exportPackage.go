@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/metadata"
+	"github.com/Arapak/sio-tool/polygon_package"
+	"github.com/Arapak/sio-tool/sinol_package"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+	"github.com/otiai10/copy"
+)
+
+const ErrorUploadNotAvailable = "uploading packages is only available on sio"
+
+// the statement is looked for under these names, $%task%$ is the name of the problem's folder
+var statementNamings = []string{"$%task%$zad.pdf", "$%task%$.pdf", "statement.pdf"}
+
+// exportedTestNames names the tests of the package in the Sinol way. Tests already named like in OI keep
+// their group, otherwise they are all put into group 1.
+func exportedTestNames(task, taskID string, tests []string) (names []string, regrouped bool) {
+	used := make(map[string]bool)
+	for _, test := range tests {
+		group, letters, ok := sinol_package.TestID(task, test)
+		name := fmt.Sprint(taskID, group, letters)
+		if !ok || used[name] {
+			break
+		}
+		used[name] = true
+		names = append(names, name)
+	}
+	if len(names) == len(tests) {
+		return
+	}
+	color.Yellow("The tests aren't named like in OI, they are all put into group 1")
+	names = make([]string, len(tests))
+	for i := range tests {
+		names[i] = sinol_package.TestName(taskID, 1, i)
+	}
+	return names, true
+}
+
+// exportProgram copies the program matching the default naming into prog/ of the package as prog/<name><ext>
+func exportProgram(root, filename, naming, task, name string) (exported string, err error) {
+	if filename == "" {
+		filename = strings.ReplaceAll(config.Instance.DefaultNaming[naming], "$%task%$", task)
+	}
+	if filename == "" || !util.FileExists(filename) {
+		return "", nil
+	}
+	exported = filepath.Join("prog", name+filepath.Ext(filename))
+	return exported, copy.Copy(filename, filepath.Join(root, exported))
+}
+
+// localPackageConfig returns config.yml of the local package, or the one made from package.xml of a Polygon package
+func localPackageConfig(packagePath string) (local *sinol_package.Config, err error) {
+	polygon, err := polygon_package.Load(packagePath)
+	if polygon == nil || err != nil {
+		return sinol_package.LoadConfig(packagePath)
+	}
+	testset := polygon.Testset()
+	return &sinol_package.Config{
+		Title:       polygon.Name(),
+		TimeLimit:   testset.TimeLimit,
+		MemoryLimit: testset.MemoryLimit / 1024,
+	}, nil
+}
+
+// exportedConfig takes the limits from the problem metadata, the rest (and the limits missing in the metadata)
+// from the config of the local package
+func exportedConfig(problem *metadata.Problem, local *sinol_package.Config, task string, regrouped bool) *sinol_package.Config {
+	exported := &sinol_package.Config{Title: problem.Name}
+	if local != nil {
+		*exported = *local
+		if problem.Name != "" {
+			exported.Title = problem.Name
+		}
+	}
+	if exported.Title == "" {
+		exported.Title = task
+	}
+	if problem.TimeLimitInSeconds > 0 {
+		exported.TimeLimit = int(problem.TimeLimitInSeconds * 1000)
+	}
+	if problem.MemoryLimitInMegabytes > 0 {
+		exported.MemoryLimit = int(problem.MemoryLimitInMegabytes * 1024)
+	}
+	// the groups and the tests of the local package don't exist anymore
+	if regrouped {
+		exported.Scores = nil
+		exported.TimeLimits = nil
+		exported.MemoryLimits = nil
+	}
+	return exported
+}
+
+// buildExportedPackage assembles the Sinol package of the problem in the current folder in root
+func buildExportedPackage(root, task string) (err error) {
+	taskID := filepath.Base(root)
+	problem, err := metadata.Load(".")
+	if err != nil {
+		return
+	}
+	packagePath, in, out, err := findPackageTests()
+	if err != nil {
+		return
+	}
+	local, err := localPackageConfig(packagePath)
+	if err != nil {
+		return
+	}
+
+	names, regrouped := exportedTestNames(task, taskID, in)
+	for i, name := range names {
+		if err = copy.Copy(filepath.Join(packagePath, in[i]), filepath.Join(root, "in", name+".in")); err != nil {
+			return
+		}
+		if err = copy.Copy(filepath.Join(packagePath, out[i]), filepath.Join(root, "out", name+".out")); err != nil {
+			return
+		}
+	}
+	color.Green("Exported %v tests", len(names))
+
+	model, err := exportProgram(root, Args.File, "solve", task, taskID)
+	if err != nil {
+		return
+	}
+	if model == "" {
+		return fmt.Errorf("cannot find the model solution of %v", task)
+	}
+	color.Green("Model solution: %v", model)
+	if brute, err := exportProgram(root, "", "brute", task, taskID+"s1"); err != nil {
+		return err
+	} else if brute != "" {
+		color.Green("Brute force solution: %v", brute)
+	}
+	checker, err := exportProgram(root, problem.Checker, "checker", task, taskID+"chk")
+	if err != nil {
+		return
+	}
+	if checker != "" {
+		color.Green("Checker: %v", checker)
+		if source, err := os.ReadFile(filepath.Join(root, checker)); err == nil && bytes.Contains(source, []byte("testlib.h")) {
+			color.Yellow("The checker uses testlib, sio expects it to print OK or WRONG like a Sinol checker")
+		}
+	}
+
+	for _, naming := range statementNamings {
+		statement := strings.ReplaceAll(naming, "$%task%$", task)
+		if util.FileExists(statement) {
+			if err = copy.Copy(statement, filepath.Join(root, "doc", taskID+"zad.pdf")); err != nil {
+				return
+			}
+			color.Green("Statement: %v", statement)
+			break
+		}
+	}
+
+	return sinol_package.SaveConfig(root, exportedConfig(problem, local, task, regrouped))
+}
+
+// ExportPackage makes a Sinol package of the problem in the current folder and, with --upload, uploads it to sio
+func ExportPackage() (err error) {
+	if Args.Upload && getSioClient() == nil {
+		return errors.New(ErrorUploadNotAvailable)
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	task := filepath.Base(dir)
+	taskID := strings.ToLower(task)
+
+	temp, err := os.MkdirTemp("", "st-export-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(temp)
+	root := filepath.Join(temp, taskID)
+	if err = buildExportedPackage(root, task); err != nil {
+		return
+	}
+
+	if problems := sinol_package.Validate(root); len(problems) > 0 {
+		for _, problem := range problems {
+			color.Red(problem)
+		}
+		return errors.New("the package is not valid")
+	}
+
+	archive := taskID + ".zip"
+	if err = util.CreateZip(root, archive); err != nil {
+		return
+	}
+	color.Green("Saved the package to %v", archive)
+	if Args.Upload {
+		return uploadPackage(archive)
+	}
+	return
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/database_client"
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+	"github.com/k0kubun/go-ansi"
+	"github.com/olekukonko/tablewriter"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	testRun        = "test"
+	packageTestRun = "package_test"
+)
+
+const shownHashLength = 8
+
+func sourceHash(p *program) (string, error) {
+	source, err := os.ReadFile(filepath.Join(p.path, p.full))
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(source)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func historyProblem() (string, error) {
+	return os.Getwd()
+}
+
+func newRun(kind string, p *program, testIDs []string, verdicts []judge.Verdict) (run database_client.Run, err error) {
+	problem, err := historyProblem()
+	if err != nil {
+		return
+	}
+	hash, err := sourceHash(p)
+	if err != nil {
+		return
+	}
+	run = database_client.Run{Problem: problem, Kind: kind, File: p.full, SourceHash: hash, Time: time.Now()}
+	for i, verdict := range verdicts {
+		run.Results = append(run.Results, database_client.TestResult{
+			Test:              testIDs[i],
+			Status:            string(verdict.Status),
+			TimeInSeconds:     verdict.TimeInSeconds,
+			MemoryInMegabytes: verdict.MemoryInMegabytes,
+			Points:            verdict.Points,
+		})
+	}
+	return
+}
+
+// recordRun saves the run in the history, a failure is only reported as the tests have already been run
+func recordRun(kind string, p *program, testIDs []string, verdicts []judge.Verdict) {
+	err := func() error {
+		run, err := newRun(kind, p, testIDs, verdicts)
+		if err != nil {
+			return err
+		}
+		db, err := sql.Open("sqlite", config.Instance.DbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		_, err = database_client.AddRun(db, run)
+		return err
+	}()
+	if err != nil {
+		color.Red("Failed to save the run in the history: %v", err)
+	}
+}
+
+func findHistory() (runs []database_client.Run, err error) {
+	problem, err := historyProblem()
+	if err != nil {
+		return
+	}
+	db, err := sql.Open("sqlite", config.Instance.DbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %v", err)
+	}
+	defer db.Close()
+	return database_client.FindRuns(db, problem)
+}
+
+func passedTests(run database_client.Run) (passed int) {
+	for _, result := range run.Results {
+		if result.Status == string(judge.OK) {
+			passed++
+		}
+	}
+	return
+}
+
+func maxRunTime(run database_client.Run) (maxTime float64) {
+	for _, result := range run.Results {
+		maxTime = math.Max(maxTime, result.TimeInSeconds)
+	}
+	return
+}
+
+func newTable(buf *bytes.Buffer, header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(io.Writer(buf))
+	table.SetHeader(header)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+	return table
+}
+
+func printTable(buf *bytes.Buffer) {
+	scanner := bufio.NewScanner(io.Reader(buf))
+	for scanner.Scan() {
+		_, _ = ansi.Println(scanner.Text())
+	}
+}
+
+// History lists the runs of the problem in the current folder
+func History() (err error) {
+	runs, err := findHistory()
+	if err != nil {
+		return
+	}
+	if len(runs) == 0 {
+		color.Red("no runs of the problem in the current folder")
+		return
+	}
+	var buf bytes.Buffer
+	table := newTable(&buf, []string{"#", "time", "command", "file", "source", "passed", "max time"})
+	for _, run := range runs {
+		passed := fmt.Sprintf("%v/%v", passedTests(run), len(run.Results))
+		if passedTests(run) == len(run.Results) {
+			passed = util.GreenString(passed)
+		} else {
+			passed = util.RedString(passed)
+		}
+		table.Append([]string{
+			fmt.Sprint(run.ID),
+			run.Time.Format("2006-01-02 15:04:05"),
+			run.Kind,
+			run.File,
+			run.SourceHash[:shownHashLength],
+			passed,
+			fmt.Sprintf("%.3fs", maxRunTime(run)),
+		})
+	}
+	table.Render()
+	printTable(&buf)
+	return
+}
+
+func findRun(runs []database_client.Run, id string) (*database_client.Run, error) {
+	runID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid run: %v", id)
+	}
+	for i := range runs {
+		if runs[i].ID == runID {
+			return &runs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find run %v of the problem in the current folder", id)
+}
+
+// runsToCompare returns the runs given in the arguments, by default the last run and the previous one
+// of the same kind (test or package_test) of the same problem
+func runsToCompare(runs []database_client.Run) (older, newer *database_client.Run, err error) {
+	if Args.Run == "" {
+		if len(runs) == 0 {
+			return nil, nil, fmt.Errorf("there are no runs of the problem in the current folder")
+		}
+		newer = &runs[len(runs)-1]
+		for i := len(runs) - 2; i >= 0; i-- {
+			if runs[i].Kind == newer.Kind && runs[i].Problem == newer.Problem {
+				return &runs[i], newer, nil
+			}
+		}
+		return nil, nil, fmt.Errorf("there is only one %v run of the problem in the current folder", newer.Kind)
+	}
+	if older, err = findRun(runs, Args.Run); err != nil {
+		return
+	}
+	newer, err = findRun(runs, Args.OtherRun)
+	return
+}
+
+// HistoryDiff shows the tests whose verdict or time changed between two runs
+func HistoryDiff() (err error) {
+	runs, err := findHistory()
+	if err != nil {
+		return
+	}
+	older, newer, err := runsToCompare(runs)
+	if err != nil {
+		return
+	}
+	_, _ = ansi.Printf("Comparing run #%v (%v, %v) with run #%v (%v, %v)\n",
+		older.ID, older.File, older.SourceHash[:shownHashLength], newer.ID, newer.File, newer.SourceHash[:shownHashLength])
+
+	oldResults := make(map[string]database_client.TestResult)
+	for _, result := range older.Results {
+		oldResults[result.Test] = result
+	}
+	var buf bytes.Buffer
+	table := newTable(&buf, []string{"test", fmt.Sprintf("#%v", older.ID), fmt.Sprintf("#%v", newer.ID), ""})
+	regressions, fixes, changes := 0, 0, 0
+	for _, result := range newer.Results {
+		oldResult, ok := oldResults[result.Test]
+		if !ok {
+			continue
+		}
+		note := ""
+		if oldResult.Status == string(judge.OK) && result.Status != string(judge.OK) {
+			note = util.RedString("regression")
+			regressions++
+		} else if oldResult.Status != string(judge.OK) && result.Status == string(judge.OK) {
+			note = util.GreenString("fixed")
+			fixes++
+		} else if oldResult.Status != result.Status {
+			note = util.RedString("verdict changed")
+		} else if result.TimeInSeconds >= slowMinimumInSeconds && result.TimeInSeconds > slowFactor*oldResult.TimeInSeconds {
+			note = util.YellowString("slower")
+		} else {
+			continue
+		}
+		changes++
+		table.Append([]string{
+			result.Test,
+			fmt.Sprintf("%v %.3fs", oldResult.Status, oldResult.TimeInSeconds),
+			fmt.Sprintf("%v %.3fs", result.Status, result.TimeInSeconds),
+			note,
+		})
+	}
+	if changes == 0 {
+		color.Green("No differences between the runs")
+		return
+	}
+	table.Render()
+	printTable(&buf)
+	_, _ = ansi.Printf("REGRESSIONS: %v, FIXED: %v\n", util.RedString(fmt.Sprint(regressions)), util.GreenString(fmt.Sprint(fixes)))
+	return
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Arapak/sio-tool/database_client"
+)
+
+func TestRunsToCompare(t *testing.T) {
+	Args = &ParsedArgs{}
+	runs := []database_client.Run{
+		{ID: 1, Problem: "/abc", Kind: testRun},
+		{ID: 2, Problem: "/abc", Kind: packageTestRun},
+		{ID: 3, Problem: "/abc", Kind: testRun},
+		{ID: 4, Problem: "/abc", Kind: packageTestRun},
+		{ID: 5, Problem: "/abc", Kind: testRun},
+	}
+	tests := []struct {
+		runs   []database_client.Run
+		older  int
+		newer  int
+		failed bool
+	}{
+		{runs, 3, 5, false},
+		{runs[:4], 2, 4, false},
+		{runs[:2], 0, 0, true},
+		{nil, 0, 0, true},
+	}
+	for _, test := range tests {
+		older, newer, err := runsToCompare(test.runs)
+		if test.failed {
+			if err == nil {
+				t.Errorf("Expect an error, but found runs #%v and #%v.", older.ID, newer.ID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Expect runs #%v and #%v, but found %v.", test.older, test.newer, err)
+		} else if older.ID != test.older || newer.ID != test.newer {
+			t.Errorf("Expect runs #%v and #%v, but found #%v and #%v.", test.older, test.newer, older.ID, newer.ID)
+		}
+	}
+}
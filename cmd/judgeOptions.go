@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/metadata"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+)
+
+const ErrorInvalidScript = "invalid script command, please check config file"
+
+// findOptionalProgram compiles the program given by filename or, if it is empty,
+// the one matching the default naming. A nil program means that there is none.
+func findOptionalProgram(filename, naming, task string) (p *program, err error) {
+	if filename == "" {
+		filename = strings.ReplaceAll(config.Instance.DefaultNaming[naming], "$%task%$", task)
+		if filename == "" || !util.FileExists(filename) {
+			return nil, nil
+		}
+	}
+	p, err = findProgram(filename, task)
+	if err != nil {
+		return
+	}
+	if err = p.compile(); err != nil {
+		return
+	}
+	if len(p.command()) == 0 {
+		return nil, errors.New(ErrorInvalidScript)
+	}
+	return
+}
+
+func findChecker(task string) (checker *judge.Checker, p *program, err error) {
+	p, err = findOptionalProgram(Args.Checker, "checker", task)
+	if p == nil || err != nil {
+		return
+	}
+	return &judge.Checker{Command: p.command()}, p, nil
+}
+
+func findInteractor(task string) (interactor *judge.Interactor, p *program, err error) {
+	p, err = findOptionalProgram(Args.Interactor, "interactor", task)
+	if p == nil || err != nil {
+		return
+	}
+	return &judge.Interactor{Command: p.command()}, p, nil
+}
+
+// loadProblemDefaults fills the limits, the checker and the comparator not given in the arguments
+// from the metadata of the problem in the current folder
+func loadProblemDefaults() (problem *metadata.Problem, err error) {
+	problem, err = metadata.Load(".")
+	if err != nil {
+		return
+	}
+	if Args.TimeLimit == "" && problem.TimeLimitInSeconds > 0 {
+		Args.TimeLimit = strconv.FormatFloat(problem.TimeLimitInSeconds, 'f', -1, 64)
+	}
+	if Args.MemoryLimit == "" && problem.MemoryLimitInMegabytes > 0 {
+		Args.MemoryLimit = strconv.FormatFloat(problem.MemoryLimitInMegabytes, 'f', -1, 64)
+	}
+	if Args.Checker == "" {
+		Args.Checker = problem.Checker
+	}
+	if Args.Compare == "" {
+		Args.Compare = problem.Comparator
+	}
+	return
+}
+
+// problemFileIO returns the files the solutions of the problem read and write, nil for the standard input/output
+func problemFileIO(problem *metadata.Problem) *judge.FileIO {
+	fileIO := problem.FileIO()
+	if problem.IO == metadata.FileIO && fileIO == nil {
+		color.Yellow("The problem uses file input/output, but the names of the files are unknown, so the standard input/output is used.")
+	}
+	return fileIO
+}
+
+func findComparator() (comparator *judge.Comparator, err error) {
+	if Args.Compare == "" {
+		return nil, nil
+	}
+	return judge.ParseComparator(Args.Compare)
+}
+
+// backend returns the judging backend chosen by the flags or the config
+func backend() string {
+	if Args.Oiejq {
+		return config.BackendOiejq
+	}
+	if Args.Cgroup {
+		return config.BackendCgroup
+	}
+	return config.Instance.Backend
+}
+
+// runOptions prepares the backend used for running the programs
+func runOptions() (options *judge.Options, err error) {
+	options = &judge.Options{SideBySide: Args.SideBySide}
+	if backend() == config.BackendOiejq {
+		err = judge.InstallSio2Jail()
+		if err != nil {
+			return
+		}
+		options.Oiejq = &judge.OiejqOptions{MemorylimitInMegaBytes: Args.MemoryLimit, TimeLimitInSeconds: Args.TimeLimit}
+		return
+	}
+	limits, err := judge.ParseLimits(Args.TimeLimit, Args.MemoryLimit)
+	if err != nil {
+		return
+	}
+	if backend() == config.BackendCgroup {
+		if err = judge.CheckCgroup(); err != nil {
+			return
+		}
+		options.Cgroup = judge.NewCgroupOptions(limits, Args.Isolate)
+		return
+	}
+	options.Limits = &limits
+	return
+}
+
+// the generators, validators and model solutions making tests aren't judged, but they are still
+// stopped when they run for too long
+const helperTimeLimitInSeconds = 60
+
+// helperOptions runs the programs making tests with the helper time limit and without a memory limit
+func helperOptions() *judge.Options {
+	return &judge.Options{Limits: &judge.Limits{TimeLimitInSeconds: helperTimeLimitInSeconds}}
+}
+
+// runHelper runs a program making tests with the helper options
+func runHelper(command string, input io.Reader) (judge.ProcessInfo, error) {
+	return helperOptions().Run(command, input)
+}
+
+// judgeOptions prepares the run options, the comparator, the checker and the interactor according to the arguments.
+// The returned programs have to be cleaned after judging.
+func judgeOptions(task string) (options *judge.Options, programs []*program, err error) {
+	options, err = runOptions()
+	if err != nil {
+		return
+	}
+	options.Comparator, err = findComparator()
+	if err != nil {
+		return
+	}
+	checker, checkerProgram, err := findChecker(task)
+	if err != nil {
+		return
+	}
+	if checkerProgram != nil {
+		options.Checker = checker
+		programs = append(programs, checkerProgram)
+	}
+	interactor, interactorProgram, err := findInteractor(task)
+	if err != nil {
+		return
+	}
+	if interactorProgram != nil {
+		options.Interactor = interactor
+		programs = append(programs, interactorProgram)
+	}
+	return
+}
+
+func cleanPrograms(programs []*program) error {
+	for _, p := range programs {
+		if err := p.clean(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
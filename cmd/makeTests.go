@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/metadata"
+	"github.com/Arapak/sio-tool/sinol_package"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+	"github.com/k0kubun/go-ansi"
+)
+
+// the spec is copied into the package, which marks it as made by st make-tests
+const makeTestsSpec = "st-tests.txt"
+
+const defaultSpecNaming = "$%task%$-tests.txt"
+
+// findSpecProgram finds the program given in the spec or, if there is none, the one matching the default naming
+func findSpecProgram(filename, naming, task string) (p *program, err error) {
+	if filename == "" {
+		filename = strings.ReplaceAll(config.Instance.DefaultNaming[naming], "$%task%$", task)
+	}
+	p, err = findProgram(filename, task)
+	if err != nil {
+		return
+	}
+	if err = p.compile(); err != nil {
+		return
+	}
+	if len(p.command()) == 0 {
+		return nil, errors.New(ErrorInvalidScript)
+	}
+	return
+}
+
+// makeTestsPackage returns the package made by st make-tests before or a new one
+func makeTestsPackage() (packagePath string, err error) {
+	packagesPath, err := ArgsPackagePath()
+	if err != nil {
+		return
+	}
+	packages, err := os.ReadDir(packagesPath)
+	if err != nil && !os.IsNotExist(err) {
+		return
+	}
+	for _, p := range packages {
+		packagePath = filepath.Join(packagesPath, p.Name())
+		if p.IsDir() && util.FileExists(filepath.Join(packagePath, makeTestsSpec)) {
+			return
+		}
+	}
+	return getPackageNumber(packagesPath), nil
+}
+
+func processFailure(name string, processInfo judge.ProcessInfo, err error) error {
+	if err != nil {
+		return fmt.Errorf("%v - %v: %v", name, processInfo.Status, err.Error())
+	}
+	return fmt.Errorf("%v - %v", name, processInfo.Status)
+}
+
+// makeTest generates the input, validates it and runs the model solution on it
+func makeTest(test sinol_package.SpecTest, genScript, solutionScript, validatorScript string, options *judge.Options) (input, output []byte, err error) {
+	genProcessInfo, err := runHelper(genScript+" "+test.Args, strings.NewReader(""))
+	if err != nil || genProcessInfo.Status != judge.OK {
+		return nil, nil, processFailure("GEN", genProcessInfo, err)
+	}
+	input = genProcessInfo.Output
+	if validatorScript != "" {
+		validatorProcessInfo, err := runHelper(validatorScript, bytes.NewReader(input))
+		if err != nil || validatorProcessInfo.Status != judge.OK {
+			return nil, nil, fmt.Errorf("invalid test: %v", strings.TrimSpace(string(validatorProcessInfo.Stderr)))
+		}
+	}
+	solutionProcessInfo, err := options.Run(solutionScript, bytes.NewReader(input))
+	if err != nil || solutionProcessInfo.Status != judge.OK {
+		return nil, nil, processFailure("SOLUTION", solutionProcessInfo, err)
+	}
+	return input, solutionProcessInfo.Output, nil
+}
+
+// replaceTests moves the made tests into the package in place of the previous ones
+func replaceTests(packagePath, madePath string) (err error) {
+	for _, folder := range []string{"in", "out"} {
+		if err = os.RemoveAll(filepath.Join(packagePath, folder)); err != nil {
+			return
+		}
+		if err = os.Rename(filepath.Join(madePath, folder), filepath.Join(packagePath, folder)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func writeTest(packagePath, name string, input, output []byte) (err error) {
+	if err = os.WriteFile(filepath.Join(packagePath, "in", name+".in"), input, 0644); err != nil {
+		return
+	}
+	return os.WriteFile(filepath.Join(packagePath, "out", name+".out"), output, 0644)
+}
+
+// packageConfig gives config.yml the points from the spec and the limits from the problem metadata
+func packageConfig(problem *metadata.Problem, spec *sinol_package.Spec) *sinol_package.Config {
+	return &sinol_package.Config{
+		Title:       problem.Name,
+		TimeLimit:   int(problem.TimeLimitInSeconds * 1000),
+		MemoryLimit: int(problem.MemoryLimitInMegabytes * 1024),
+		Scores:      spec.Scores(),
+	}
+}
+
+// MakeTests generates the tests described by the spec into the package of the problem in the current folder
+func MakeTests() (err error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	task := filepath.Base(dir)
+	specPath := Args.File
+	if specPath == "" {
+		specPath = strings.ReplaceAll(defaultSpecNaming, "$%task%$", task)
+	}
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return
+	}
+	spec, err := sinol_package.ParseSpec(data)
+	if err != nil {
+		return fmt.Errorf("%v: %v", specPath, err)
+	}
+	problem, err := loadProblemDefaults()
+	if err != nil {
+		return
+	}
+	numberOfWorkers, err := parsePositive("--workers", Args.Workers, defaultStressWorkers)
+	if err != nil {
+		return
+	}
+
+	gen, err := findSpecProgram(spec.Generator, "gen", task)
+	if err != nil {
+		return
+	}
+	solution, err := findSpecProgram(spec.Solution, "solve", task)
+	if err != nil {
+		return
+	}
+	validator, err := findOptionalProgram(spec.Validator, "validator", task)
+	if err != nil {
+		return
+	}
+	validatorScript := ""
+	if validator != nil {
+		validatorScript = validator.command()
+	} else {
+		color.Yellow("There is no validator, the tests are not validated")
+	}
+	options, err := runOptions()
+	if err != nil {
+		return
+	}
+
+	programs := []*program{gen, solution}
+	if validator != nil {
+		programs = append(programs, validator)
+	}
+
+	packagePath, err := makeTestsPackage()
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(packagePath, os.ModePerm); err != nil {
+		return
+	}
+	// the tests are made in a temporary folder and replace the previous ones only when all of them were made
+	madePath, err := os.MkdirTemp(packagePath, "st-make-tests-")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(madePath)
+	for _, folder := range []string{"in", "out"} {
+		if err = os.MkdirAll(filepath.Join(madePath, folder), os.ModePerm); err != nil {
+			return
+		}
+	}
+
+	tests := spec.Tests(task)
+
+	wg := sync.WaitGroup{}
+	wg.Add(numberOfWorkers)
+	mu := sync.Mutex{}
+
+	currentTest := 0
+	made := 0
+	failed := 0
+
+	for i := 1; i <= numberOfWorkers; i++ {
+		go func(workerID int) {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				testNumber := currentTest
+				currentTest++
+				mu.Unlock()
+				if testNumber >= len(tests) {
+					return
+				}
+				test := tests[testNumber]
+
+				input, output, err := makeTest(test, gen.command(), solution.command(), validatorScript, options)
+				if err == nil {
+					err = writeTest(madePath, test.Name, input, output)
+				}
+
+				mu.Lock()
+				clearProgress()
+				if err != nil {
+					color.Red("%v (%v): %v", test.Name, test.Args, err.Error())
+					failed++
+				} else {
+					made++
+				}
+				_, _ = ansi.Printf("GENERATED: %v/%v", util.BlueString(fmt.Sprint(made)), len(tests))
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	color.Blue("\n----FINISHED----")
+
+	if err = cleanPrograms(programs); err != nil {
+		return
+	}
+	if failed > 0 {
+		// a new package is left empty
+		_ = os.RemoveAll(madePath)
+		_ = os.Remove(packagePath)
+		return fmt.Errorf("%v of %v tests could not be made, the tests in %v weren't changed", failed, len(tests), packagePath)
+	}
+	if err = replaceTests(packagePath, madePath); err != nil {
+		return
+	}
+	if err = os.WriteFile(filepath.Join(packagePath, makeTestsSpec), data, 0644); err != nil {
+		return
+	}
+	if err = sinol_package.SaveConfig(packagePath, packageConfig(problem, spec)); err != nil {
+		return
+	}
+	color.Green("Made %v tests in %v", made, packagePath)
+	return
+}
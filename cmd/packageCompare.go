@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/sinol_package"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+	"github.com/k0kubun/go-ansi"
+	"github.com/olekukonko/tablewriter"
+)
+
+// a solution is slow on a test when it runs slowFactor times longer than the fastest one,
+// times below slowMinimumInSeconds are too noisy to compare
+const slowFactor = 2
+const slowMinimumInSeconds = 0.1
+
+// the programs of the task which make or judge the tests aren't its solutions
+var notSolutionNamings = []string{"gen", "validator", "checker", "interactor"}
+
+// isSolution is true if the file belongs to the task and isn't named like one of its programs making or judging tests
+func isSolution(filename, task string, defaultNaming map[string]string) bool {
+	for _, naming := range notSolutionNamings {
+		if filename == strings.ReplaceAll(defaultNaming[naming], "$%task%$", task) {
+			return false
+		}
+	}
+	return judge.ExtractTaskName(strings.TrimSuffix(filename, filepath.Ext(filename))) == task
+}
+
+// findSolutions returns the solutions given in the arguments or, with --all,
+// the files of the task named like the current folder (e.g. abc.cpp, abc-slow.cpp)
+func findSolutions() (solutions []*program, err error) {
+	cfg := config.Instance
+	if !Args.AllSolutions {
+		for _, filename := range append([]string{Args.File}, Args.Solutions...) {
+			p, err := findProgram(filename, "")
+			if err != nil {
+				return nil, err
+			}
+			p.task = judge.ExtractTaskName(p.file)
+			solutions = append(solutions, p)
+		}
+		return
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	task := filepath.Base(dir)
+	codes, err := getCode("", cfg.Template, map[string]struct{}{})
+	if err != nil {
+		return
+	}
+	for _, code := range codes {
+		if !isSolution(code.Name, task, cfg.DefaultNaming) {
+			continue
+		}
+		solutions = append(solutions, newProgram(code.Name, cfg.Template[code.Index[0]], task))
+	}
+	if len(solutions) == 0 {
+		return nil, fmt.Errorf("cannot find any solution of %v in the current folder", task)
+	}
+	return
+}
+
+// PackageCompare runs several solutions on the same package and shows their verdicts side by side
+func PackageCompare() (err error) {
+	solutions, err := findSolutions()
+	if err != nil {
+		return
+	}
+	task := solutions[0].task
+	packagePath, in, out, err := findPackageTests()
+	if err != nil {
+		return
+	}
+	sinolConfig, problem, err := loadPackageDefaults(packagePath)
+	if err != nil {
+		return
+	}
+	// only the local solutions are saved in the history
+	localSolutions := len(solutions)
+	if Args.Official {
+		official, err := officialSolutions(packagePath, task, in)
+		if err != nil {
+			return err
+		}
+		if len(official) == 0 {
+			color.Yellow("There are no official solutions in the package")
+		}
+		solutions = append(solutions, official...)
+	}
+
+	for _, p := range solutions {
+		if err = p.useProfile(Args.Profile); err != nil {
+			return
+		}
+		if err = p.compile(); err != nil {
+			return
+		}
+	}
+	options, programs, err := judgeOptions(task)
+	if err != nil {
+		return
+	}
+	options.FileIO = problemFileIO(problem)
+	solutionOptions := make([]*judge.Options, len(solutions))
+	runScripts := make([]string, len(solutions))
+	for i, p := range solutions {
+		o := *options
+		if err = p.applyProfile(&o); err != nil {
+			return
+		}
+		solutionOptions[i] = &o
+		runScripts[i] = p.command()
+		if len(runScripts[i]) == 0 {
+			return errors.New(ErrorInvalidScript)
+		}
+	}
+
+	numberOfWorkers := 10
+
+	wg := sync.WaitGroup{}
+	wg.Add(numberOfWorkers)
+	mu := sync.Mutex{}
+
+	runs := len(in) * len(solutions)
+	currentRun := 0
+	finishedRuns := 0
+	verdicts := make([][]judge.Verdict, len(in))
+	for i := range verdicts {
+		verdicts[i] = make([]judge.Verdict, len(solutions))
+	}
+
+	for i := 1; i <= numberOfWorkers; i++ {
+		go func(workerID int) {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				run := currentRun
+				currentRun++
+				mu.Unlock()
+				if run >= runs {
+					return
+				}
+				testNumber, solution := run/len(solutions), run%len(solutions)
+
+				verdict := judge.Judge(filepath.Join(packagePath, in[testNumber]), filepath.Join(packagePath, out[testNumber]), in[testNumber], runScripts[solution], testOptions(solutionOptions[solution], sinolConfig, task, in[testNumber]))
+
+				mu.Lock()
+				verdicts[testNumber][solution] = verdict
+				finishedRuns++
+				ansi.EraseInLine(2)
+				ansi.CursorHorizontalAbsolute(0)
+				_, _ = ansi.Printf("RAN: %v/%v", util.BlueString(fmt.Sprint(finishedRuns)), runs)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	color.Blue("\n----FINISHED----")
+
+	printComparison(task, sinolConfig, in, solutions, verdicts)
+	for j, p := range solutions[:localSolutions] {
+		solutionVerdicts := make([]judge.Verdict, len(in))
+		for i := range in {
+			solutionVerdicts[i] = verdicts[i][j]
+		}
+		recordRun(packageTestRun, p, in, solutionVerdicts)
+	}
+
+	if err = cleanPrograms(programs); err != nil {
+		return
+	}
+	for _, p := range solutions {
+		if err = p.clean(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// slowSolutions marks the solutions much slower than the fastest one which passed the test
+func slowSolutions(verdicts []judge.Verdict) (slow []bool, anySlow bool) {
+	fastest := math.Inf(1)
+	for _, verdict := range verdicts {
+		if verdict.Status == judge.OK {
+			fastest = math.Min(fastest, verdict.TimeInSeconds)
+		}
+	}
+	slow = make([]bool, len(verdicts))
+	for i, verdict := range verdicts {
+		if verdict.TimeInSeconds >= slowMinimumInSeconds && verdict.TimeInSeconds > slowFactor*fastest {
+			slow[i], anySlow = true, true
+		}
+	}
+	return
+}
+
+func differentVerdicts(verdicts []judge.Verdict) bool {
+	for _, verdict := range verdicts {
+		if verdict.Status != verdicts[0].Status {
+			return true
+		}
+	}
+	return false
+}
+
+// solutionScore returns the OI score of the solution if the tests are grouped like in OI,
+// or the number of passed tests
+func solutionScore(task string, config *sinol_package.Config, tests []string, verdicts []judge.Verdict) string {
+	groups, ok := groupTests(task, tests)
+	if !ok {
+		passed := 0
+		for _, verdict := range verdicts {
+			if verdict.Status == judge.OK {
+				passed++
+			}
+		}
+		return fmt.Sprintf("%v/%v", passed, len(verdicts))
+	}
+	var groupIDs []int
+	for group := range groups {
+		groupIDs = append(groupIDs, group)
+	}
+	points, maxPoints := 0.0, 0.0
+	for _, s := range newSubtasks(groups, sinol_package.Scores(config, groupIDs), verdicts, testTimeLimits(task, config, tests)) {
+		points += s.oiPoints
+		maxPoints += s.maxPoints
+	}
+	return fmt.Sprintf("%v/%v", formatPoints(points), formatPoints(maxPoints))
+}
+
+// solutionName shows the folder of the solutions from the package, e.g. prog/abc.cpp
+func solutionName(p *program) string {
+	if p.path == "" {
+		return p.full
+	}
+	return filepath.Join(filepath.Base(p.path), p.full)
+}
+
+func printComparison(task string, config *sinol_package.Config, tests []string, solutions []*program, verdicts [][]judge.Verdict) {
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(io.Writer(&buf))
+	header := []string{"test"}
+	for _, p := range solutions {
+		header = append(header, solutionName(p))
+	}
+	table.SetHeader(append(header, ""))
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.SetAutoFormatHeaders(false)
+
+	maxTimes := make([]float64, len(solutions))
+	for i, test := range tests {
+		slow, anySlow := slowSolutions(verdicts[i])
+		row := []string{strings.TrimSuffix(filepath.Base(test), filepath.Ext(test))}
+		for j, verdict := range verdicts[i] {
+			maxTimes[j] = math.Max(maxTimes[j], verdict.TimeInSeconds)
+			cell := fmt.Sprintf("%v %.3fs", verdict.Status, verdict.TimeInSeconds)
+			if verdict.Status != judge.OK {
+				cell = util.RedString(cell)
+			} else if slow[j] {
+				cell = util.YellowString(cell)
+			} else {
+				cell = util.GreenString(cell)
+			}
+			row = append(row, cell)
+		}
+		note := ""
+		if differentVerdicts(verdicts[i]) {
+			note = util.RedString("verdicts differ")
+		} else if anySlow {
+			note = util.YellowString("slow")
+		}
+		table.Append(append(row, note))
+	}
+
+	footer := []string{"max time"}
+	for _, maxTime := range maxTimes {
+		footer = append(footer, fmt.Sprintf("%.3fs", maxTime))
+	}
+	table.Append(append(footer, ""))
+	score := []string{"score"}
+	for j := range solutions {
+		solutionVerdicts := make([]judge.Verdict, len(tests))
+		for i := range tests {
+			solutionVerdicts[i] = verdicts[i][j]
+		}
+		score = append(score, util.BlueString(solutionScore(task, config, tests, solutionVerdicts)))
+	}
+	table.Append(append(score, ""))
+	table.Render()
+
+	scanner := bufio.NewScanner(io.Reader(&buf))
+	for scanner.Scan() {
+		_, _ = ansi.Println(scanner.Text())
+	}
+}
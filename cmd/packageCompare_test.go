@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+func TestIsSolution(t *testing.T) {
+	defaultNaming := map[string]string{
+		"solve":      "$%task%$.cpp",
+		"brute":      "$%task%$-brute.cpp",
+		"gen":        "$%task%$-gen.cpp",
+		"validator":  "$%task%$-val.cpp",
+		"checker":    "$%task%$-chk.cpp",
+		"interactor": "$%task%$-int.cpp",
+	}
+	tests := []struct {
+		filename string
+		expect   bool
+	}{
+		{"abc.cpp", true},
+		{"abc-slow.cpp", true},
+		{"abc-brute.cpp", true},
+		{"abc-gen.cpp", false},
+		{"abc-val.cpp", false},
+		{"abc-chk.cpp", false},
+		{"abc-int.cpp", false},
+		{"abd.cpp", false},
+	}
+	for _, test := range tests {
+		if result := isSolution(test.filename, "abc", defaultNaming); result != test.expect {
+			t.Errorf("Expect %v, but found %v for %v.", test.expect, result, test.filename)
+		}
+	}
+}
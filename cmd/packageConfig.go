@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/metadata"
+	"github.com/Arapak/sio-tool/polygon_package"
+	"github.com/Arapak/sio-tool/sinol_package"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+	"github.com/k0kubun/go-ansi"
+)
+
+func formatTimeLimit(milliseconds int) string {
+	return strconv.FormatFloat(float64(milliseconds)/1000, 'f', -1, 64)
+}
+
+func formatMemoryLimit(kilobytes int) string {
+	return strconv.FormatFloat(float64(kilobytes)/1024, 'f', -1, 64)
+}
+
+// loadPackageConfig reads config.yml of the package and fills the limits not given in the arguments.
+// The limits of single groups and tests are dropped when the arguments give the limit.
+func loadPackageConfig(packagePath string) (sinolConfig *sinol_package.Config, err error) {
+	sinolConfig, err = sinol_package.LoadConfig(packagePath)
+	if sinolConfig == nil || err != nil {
+		return
+	}
+	if Args.TimeLimit != "" {
+		sinolConfig.TimeLimits = nil
+	} else if sinolConfig.TimeLimit > 0 {
+		Args.TimeLimit = formatTimeLimit(sinolConfig.TimeLimit)
+	}
+	if Args.MemoryLimit != "" {
+		sinolConfig.MemoryLimits = nil
+	} else if sinolConfig.MemoryLimit > 0 {
+		Args.MemoryLimit = formatMemoryLimit(sinolConfig.MemoryLimit)
+	}
+	return
+}
+
+// testOptions returns the options with the limits config.yml gives for the test
+func testOptions(options *judge.Options, sinolConfig *sinol_package.Config, task, test string) *judge.Options {
+	if sinolConfig == nil || !sinolConfig.HasTestLimits() {
+		return options
+	}
+	timeLimit, memoryLimit := sinolConfig.TestLimits(task, test)
+	o := *options
+	if o.Oiejq != nil {
+		oiejq := *o.Oiejq
+		if timeLimit > 0 {
+			oiejq.TimeLimitInSeconds = formatTimeLimit(timeLimit)
+		}
+		if memoryLimit > 0 {
+			oiejq.MemorylimitInMegaBytes = formatMemoryLimit(memoryLimit)
+		}
+		o.Oiejq = &oiejq
+	}
+	setLimits := func(limits *judge.Limits) {
+		if timeLimit > 0 {
+			limits.TimeLimitInSeconds = float64(timeLimit) / 1000
+		}
+		if memoryLimit > 0 {
+			limits.MemoryLimitInMegabytes = float64(memoryLimit) / 1024
+		}
+	}
+	if o.Cgroup != nil {
+		cgroup := *o.Cgroup
+		setLimits(&cgroup.Limits)
+		o.Cgroup = &cgroup
+	}
+	if o.Limits != nil {
+		limits := *o.Limits
+		setLimits(&limits)
+		o.Limits = &limits
+	}
+	return &o
+}
+
+// testTimeLimits returns the time limit of every test used for the time-based scoring
+func testTimeLimits(task string, sinolConfig *sinol_package.Config, tests []string) []float64 {
+	timeLimit := scoringTimeLimit(sinolConfig)
+	timeLimits := make([]float64, len(tests))
+	for i, test := range tests {
+		timeLimits[i] = timeLimit
+		if sinolConfig != nil && len(sinolConfig.TimeLimits) > 0 {
+			if testTimeLimit, _ := sinolConfig.TestLimits(task, test); testTimeLimit > 0 {
+				timeLimits[i] = float64(testTimeLimit) / 1000
+			}
+		}
+	}
+	return timeLimits
+}
+
+// packageProgram makes the program of a file of the package, the templates run the programs
+// by a path relative to the current folder
+func packageProgram(packagePath, file, task string) (p *program, err error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	path, err := filepath.Rel(dir, filepath.Join(packagePath, file))
+	if err != nil {
+		return
+	}
+	return findProgram(path, task)
+}
+
+// findPackagePrograms finds the programs in prog/ of the package whose names (without the extension) match
+func findPackagePrograms(packagePath, task string, matches func(name string) bool) (programs []*program, err error) {
+	entries, err := os.ReadDir(filepath.Join(packagePath, "prog"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !matches(strings.TrimSuffix(name, filepath.Ext(name))) {
+			continue
+		}
+		if p, err := packageProgram(packagePath, filepath.Join("prog", name), task); err == nil {
+			programs = append(programs, p)
+		}
+	}
+	return
+}
+
+// findPackageProgram finds the first matching program in prog/ of the package, nil if there is none
+func findPackageProgram(packagePath, task string, matches func(name string) bool) (p *program, err error) {
+	programs, err := findPackagePrograms(packagePath, task, matches)
+	if len(programs) == 0 || err != nil {
+		return
+	}
+	return programs[0], nil
+}
+
+// usePackageChecker uses the checker from prog/ of the package, unless another one is given in the arguments
+func usePackageChecker(packagePath string) (err error) {
+	if Args.Checker != "" {
+		return
+	}
+	p, err := findPackageProgram(packagePath, "", func(name string) bool {
+		return strings.Contains(name, "chk")
+	})
+	if p == nil || err != nil {
+		return
+	}
+	Args.Checker = filepath.Join(p.path, p.full)
+	color.Cyan("Using the checker of the package: %v", Args.Checker)
+	return
+}
+
+// missingOutputs returns the inputs from in/ of the package without an output in out/
+func missingOutputs(packagePath string) (inputs []string, err error) {
+	entries, err := os.ReadDir(filepath.Join(packagePath, "in"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".in" {
+			continue
+		}
+		output := strings.TrimSuffix(name, ".in") + ".out"
+		if !util.FileExists(filepath.Join(packagePath, "out", output)) {
+			inputs = append(inputs, name)
+		}
+	}
+	return
+}
+
+// generateMissingOutputs runs the model solution from prog/ of the package on the inputs without an output
+func generateMissingOutputs(packagePath string) (err error) {
+	inputs, err := missingOutputs(packagePath)
+	if len(inputs) == 0 || err != nil {
+		return
+	}
+	task := sinol_package.TaskID(inputs[0])
+	solution, err := findPackageProgram(packagePath, task, func(name string) bool {
+		return name == task
+	})
+	if err != nil {
+		return
+	}
+	if solution == nil {
+		color.Yellow("%v tests have no output and there is no model solution in prog/ to generate them", len(inputs))
+		return
+	}
+	color.Cyan("Generating %v missing outputs with %v", len(inputs), filepath.Join(solution.path, solution.full))
+	if err = solution.compile(); err != nil {
+		return
+	}
+	defer solution.clean()
+	if len(solution.command()) == 0 {
+		return errors.New(ErrorInvalidScript)
+	}
+	if err = os.MkdirAll(filepath.Join(packagePath, "out"), os.ModePerm); err != nil {
+		return
+	}
+	for i, name := range inputs {
+		input, err := os.ReadFile(filepath.Join(packagePath, "in", name))
+		if err != nil {
+			return err
+		}
+		processInfo, err := runHelper(solution.command(), bytes.NewReader(input))
+		if err != nil || processInfo.Status != judge.OK {
+			fmt.Println()
+			return processFailure(name, processInfo, err)
+		}
+		output := filepath.Join(packagePath, "out", strings.TrimSuffix(name, ".in")+".out")
+		if err = os.WriteFile(output, processInfo.Output, 0644); err != nil {
+			return err
+		}
+		clearProgress()
+		_, _ = ansi.Printf("GENERATED: %v/%v", util.BlueString(fmt.Sprint(i+1)), len(inputs))
+	}
+	fmt.Println()
+	return
+}
+
+// loadPackageDefaults fills the limits and the checker not given in the arguments from the package,
+// then from the metadata of the problem
+func loadPackageDefaults(packagePath string) (sinolConfig *sinol_package.Config, problem *metadata.Problem, err error) {
+	polygon, err := polygon_package.Load(packagePath)
+	if err != nil {
+		return
+	}
+	if polygon != nil {
+		usePolygonDefaults(packagePath, polygon)
+	} else {
+		if sinolConfig, err = loadPackageConfig(packagePath); err != nil {
+			return
+		}
+		if err = usePackageChecker(packagePath); err != nil {
+			return
+		}
+	}
+	problem, err = loadProblemDefaults()
+	return
+}
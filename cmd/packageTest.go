@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -69,6 +68,7 @@ var testPatterns = [...]testPattern{
 	{`^in/(\w+)\.in$`, `^out/(\w+)\.out$`},
 	{`^in/in(\w+)$`, `^out/out(\w+)$`},
 	{`^in/(\w+)$`, `^out/(\w+)$`},
+	{`^tests/(\w+)$`, `^tests/(\w+)\.a$`},
 }
 
 func checkMatching(s string, pattern string) (string, bool) {
@@ -139,10 +139,13 @@ func printVerdict(verdict judge.Verdict, testID string) {
 	}
 }
 
-func printReport(m map[judge.VerdictStatus]int, testsRan int, maxTime, maxMemory float64) {
+func printReport(m map[judge.VerdictStatus]int, testsRan int, maxTime, maxMemory, points float64) {
 	_, _ = ansi.Printf("TESTS RAN: %v", util.BlueString(fmt.Sprint(testsRan)))
 	_, _ = ansi.Printf(" MAX TIME: %0.3fs", maxTime)
 	_, _ = ansi.Printf(" MAX MEMORY: %v", judge.ParseMemory(maxMemory))
+	if testsRan > 0 {
+		_, _ = ansi.Printf(" POINTS: %0.1f%%", points/float64(testsRan))
+	}
 	for _, status := range judge.Verdicts {
 		if num, ok := m[status]; ok {
 			if status == judge.OK {
@@ -154,59 +157,67 @@ func printReport(m map[judge.VerdictStatus]int, testsRan int, maxTime, maxMemory
 	}
 }
 
+// findPackageTests finds the package of the problem in the current folder and its tests,
+// generating the ones missing in the package
+func findPackageTests() (packagePath string, in []string, out []string, err error) {
+	packagesPath, err := ArgsPackagePath()
+	if err != nil {
+		return
+	}
+	packagePath, err = getOnePackage(packagesPath)
+	if err != nil {
+		return
+	}
+	packagePath = filepath.Join(packagesPath, packagePath)
+	if err = generateMissingTests(packagePath); err != nil {
+		return
+	}
+	in, out, err = getAllTests(packagePath)
+	return
+}
+
 func PackageTest() (err error) {
 	cfg := config.Instance
 	if len(cfg.Template) == 0 {
 		return errors.New("you have to add at least one code template by `st config`")
 	}
+	if Args.AllSolutions || Args.Official || len(Args.Solutions) > 0 {
+		return PackageCompare()
+	}
 
-	filename, index, err := getOneCode(Args.File, cfg.Template, map[string]struct{}{})
+	p, err := findProgram(Args.File, "")
 	if err != nil {
 		return
 	}
-	template := cfg.Template[index]
-	path, full := filepath.Split(filename)
-	ext := filepath.Ext(filename)
-	file := full[:len(full)-len(ext)]
-	rand := util.RandString(8)
-
-	packagesPath, err := ArgsPackagePath()
-	if err != nil {
+	p.task = judge.ExtractTaskName(p.file)
+	if err = p.useProfile(Args.Profile); err != nil {
 		return
 	}
-	packagePath, err := getOnePackage(packagesPath)
+	packagePath, in, out, err := findPackageTests()
 	if err != nil {
 		return
 	}
-	packagePath = filepath.Join(packagesPath, packagePath)
-	in, out, err := getAllTests(packagePath)
+	sinolConfig, problem, err := loadPackageDefaults(packagePath)
 	if err != nil {
 		return
 	}
 
-	filter := func(cmd string) string {
-		cmd = strings.ReplaceAll(cmd, "$%rand%$", rand)
-		cmd = strings.ReplaceAll(cmd, "$%path%$", path)
-		cmd = strings.ReplaceAll(cmd, "$%full%$", full)
-		cmd = strings.ReplaceAll(cmd, "$%file%$", file)
-		return cmd
+	if err = checkReportFormat(); err != nil {
+		return
 	}
 
-	run := func(script string) error {
-		if s := filter(script); len(s) > 0 {
-			fmt.Println(s)
-			cmds := util.SplitCmd(s)
-			cmd := exec.Command(cmds[0], cmds[1:]...)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
-		}
-		return nil
+	if err = p.compile(); err != nil {
+		return
 	}
 
-	if err = run(template.BeforeScript); err != nil {
+	options, programs, err := judgeOptions(p.task)
+	if err != nil {
+		return
+	}
+	if err = p.applyProfile(options); err != nil {
 		return
 	}
+	options.FileIO = problemFileIO(problem)
 
 	numberOfWorkers := 10
 
@@ -216,21 +227,17 @@ func PackageTest() (err error) {
 
 	currentTestNumber := 0
 
-	runScript := filter(template.Script)
-
-	var oiejqOptions *judge.OiejqOptions
-	if Args.Oiejq {
-		err = judge.InstallSio2Jail()
-		if err != nil {
-			return
-		}
-		oiejqOptions = &judge.OiejqOptions{MemorylimitInMegaBytes: Args.MemoryLimit, TimeLimitInSeconds: Args.TimeLimit}
+	runScript := p.command()
+	if len(runScript) == 0 {
+		return errors.New(ErrorInvalidScript)
 	}
 
 	m := make(map[judge.VerdictStatus]int)
 	testsRan := 0
 	maxTime := 0.0
 	maxMemory := 0.0
+	points := 0.0
+	verdicts := make([]judge.Verdict, len(in))
 
 	for i := 1; i <= numberOfWorkers; i++ {
 		go func(workerID int) {
@@ -248,24 +255,48 @@ func PackageTest() (err error) {
 				}
 				mu.Unlock()
 
-				verdict := judge.Judge(filepath.Join(packagePath, in[testNumber]), filepath.Join(packagePath, out[testNumber]), in[testNumber], runScript, oiejqOptions)
+				verdict := judge.Judge(filepath.Join(packagePath, in[testNumber]), filepath.Join(packagePath, out[testNumber]), in[testNumber], runScript, testOptions(options, sinolConfig, p.task, in[testNumber]))
 
 				mu.Lock()
 				ansi.EraseInLine(2)
 				ansi.CursorHorizontalAbsolute(0)
 				if Args.Verbose {
-					printVerdict(verdict, in[testNumber])
+					printVerdictWithStderr(verdict, in[testNumber], filepath.Join(packagePath, in[testNumber]))
+				} else {
+					saveStderr(verdict, filepath.Join(packagePath, in[testNumber]))
 				}
+				verdicts[testNumber] = verdict
 				m[verdict.Status]++
 				testsRan++
 				maxTime = math.Max(maxTime, verdict.TimeInSeconds)
 				maxMemory = math.Max(maxMemory, verdict.MemoryInMegabytes)
-				printReport(m, testsRan, maxTime, maxMemory)
+				points += verdict.Points
+				printReport(m, testsRan, maxTime, maxMemory, points)
 				mu.Unlock()
 			}
 		}(i)
 	}
 	wg.Wait()
 	color.Blue("\n----FINISHED----")
-	return
+	printSubtasks(p.task, sinolConfig, in, verdicts)
+	recordRun(packageTestRun, p, in, verdicts)
+	if err = writeReport(newReport(p.task, in, verdicts)); err != nil {
+		return
+	}
+	if Args.DebugFailing {
+		for i, verdict := range verdicts {
+			if verdict.Status == judge.OK {
+				continue
+			}
+			input, err := os.ReadFile(filepath.Join(packagePath, in[i]))
+			if err != nil {
+				return err
+			}
+			if err = rerunWithDebugProfile(p, in[i], input); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return cleanPrograms(programs)
 }
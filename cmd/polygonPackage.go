@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/polygon_package"
+	"github.com/Arapak/sio-tool/sinol_package"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+	"github.com/k0kubun/go-ansi"
+)
+
+// usePolygonDefaults fills the limits and the checker not given in the arguments from package.xml
+func usePolygonDefaults(packagePath string, polygon *polygon_package.Package) {
+	testset := polygon.Testset()
+	if Args.TimeLimit == "" && testset.TimeLimit > 0 {
+		Args.TimeLimit = formatTimeLimit(testset.TimeLimit)
+	}
+	if Args.MemoryLimit == "" && testset.MemoryLimit > 0 {
+		Args.MemoryLimit = strconv.FormatFloat(float64(testset.MemoryLimit)/1024/1024, 'f', -1, 64)
+	}
+	if Args.Checker != "" || polygon.Checker.Path == "" || !util.FileExists(filepath.Join(packagePath, polygon.Checker.Path)) {
+		return
+	}
+	checker, err := packageProgram(packagePath, polygon.Checker.Path, "")
+	if err != nil {
+		color.Yellow("Cannot use the checker of the package: %v", err.Error())
+		return
+	}
+	Args.Checker = filepath.Join(checker.path, checker.full)
+	color.Cyan("Using the checker of the package: %v", Args.Checker)
+}
+
+// compiledProgram compiles the program once, the compiled programs have to be cleaned
+func compiledProgram(compiled map[string]*program, packagePath, file string) (p *program, err error) {
+	if p, ok := compiled[file]; ok {
+		return p, nil
+	}
+	if p, err = packageProgram(packagePath, file, ""); err != nil {
+		return
+	}
+	if err = p.compile(); err != nil {
+		return
+	}
+	if len(p.command()) == 0 {
+		return nil, errors.New(ErrorInvalidScript)
+	}
+	compiled[file] = p
+	return
+}
+
+func writePackageFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// generatePolygonInputs runs the generation commands of package.xml (e.g. "gen 10 1") for the tests
+// the package doesn't include
+func generatePolygonInputs(packagePath string, polygon *polygon_package.Package, compiled map[string]*program) (err error) {
+	testset := polygon.Testset()
+	generated := 0
+	for i, test := range testset.Tests {
+		input := filepath.Join(packagePath, testset.InputPath(i+1))
+		if util.FileExists(input) {
+			continue
+		}
+		fields := strings.Fields(test.Cmd)
+		if test.Method != "generated" || len(fields) == 0 {
+			color.Yellow("Test %v is not in the package and cannot be generated", i+1)
+			continue
+		}
+		generator := polygon.Executable(fields[0])
+		if generator == nil {
+			color.Yellow("Test %v cannot be generated, there is no %v in the package", i+1, fields[0])
+			continue
+		}
+		p, err := compiledProgram(compiled, packagePath, generator.Path)
+		if err != nil {
+			return err
+		}
+		processInfo, err := runHelper(p.command()+" "+strings.Join(fields[1:], " "), strings.NewReader(""))
+		if err != nil || processInfo.Status != judge.OK {
+			color.Yellow("Test %v cannot be generated: %v", i+1, processFailure(test.Cmd, processInfo, err).Error())
+			continue
+		}
+		if err = writePackageFile(input, processInfo.Output); err != nil {
+			return err
+		}
+		generated++
+		clearProgress()
+		_, _ = ansi.Printf("GENERATED: %v", util.BlueString(fmt.Sprint(generated)))
+	}
+	if generated > 0 {
+		fmt.Println()
+	}
+	return
+}
+
+// generatePolygonAnswers runs the main solution of the package on the tests without an answer
+func generatePolygonAnswers(packagePath string, polygon *polygon_package.Package, compiled map[string]*program) (err error) {
+	testset := polygon.Testset()
+	var missing []int
+	for i := range testset.Tests {
+		if util.FileExists(filepath.Join(packagePath, testset.InputPath(i+1))) && !util.FileExists(filepath.Join(packagePath, testset.AnswerPath(i+1))) {
+			missing = append(missing, i+1)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	mainSolution := polygon.MainSolution()
+	if mainSolution == nil {
+		color.Yellow("%v tests have no answer and there is no main solution in the package to generate them", len(missing))
+		return
+	}
+	color.Cyan("Generating %v missing answers with %v", len(missing), mainSolution.Source.Path)
+	p, err := compiledProgram(compiled, packagePath, mainSolution.Source.Path)
+	if err != nil {
+		return
+	}
+	for i, index := range missing {
+		input, err := os.ReadFile(filepath.Join(packagePath, testset.InputPath(index)))
+		if err != nil {
+			return err
+		}
+		processInfo, err := runHelper(p.command(), bytes.NewReader(input))
+		if err != nil || processInfo.Status != judge.OK {
+			fmt.Println()
+			return processFailure(testset.InputPath(index), processInfo, err)
+		}
+		if err = writePackageFile(filepath.Join(packagePath, testset.AnswerPath(index)), processInfo.Output); err != nil {
+			return err
+		}
+		clearProgress()
+		_, _ = ansi.Printf("GENERATED: %v/%v", util.BlueString(fmt.Sprint(i+1)), len(missing))
+	}
+	fmt.Println()
+	return
+}
+
+// generatePolygonTests makes the tests and the answers which a Polygon package doesn't include
+func generatePolygonTests(packagePath string, polygon *polygon_package.Package) (err error) {
+	compiled := make(map[string]*program)
+	defer func() {
+		for _, p := range compiled {
+			_ = p.clean()
+		}
+	}()
+	if err = generatePolygonInputs(packagePath, polygon, compiled); err != nil {
+		return
+	}
+	return generatePolygonAnswers(packagePath, polygon, compiled)
+}
+
+// generateMissingTests makes the tests which the package can generate but doesn't include
+func generateMissingTests(packagePath string) error {
+	polygon, err := polygon_package.Load(packagePath)
+	if err != nil {
+		return err
+	}
+	if polygon != nil {
+		return generatePolygonTests(packagePath, polygon)
+	}
+	return generateMissingOutputs(packagePath)
+}
+
+// officialSolutions returns the solutions of the package: the ones from package.xml of a Polygon package
+// or the ones from prog/ of a Sinol package (e.g. abc.cpp, abc2.cpp, abcs1.cpp, abcb1.cpp)
+func officialSolutions(packagePath, task string, tests []string) (solutions []*program, err error) {
+	polygon, err := polygon_package.Load(packagePath)
+	if err != nil {
+		return
+	}
+	if polygon == nil {
+		if len(tests) == 0 {
+			return
+		}
+		solutionName := regexp.MustCompile(`^` + regexp.QuoteMeta(sinol_package.TaskID(tests[0])) + `([sb]?\d+)?$`)
+		return findPackagePrograms(packagePath, task, solutionName.MatchString)
+	}
+	for _, solution := range polygon.Solutions {
+		if !util.FileExists(filepath.Join(packagePath, solution.Source.Path)) {
+			continue
+		}
+		p, err := packageProgram(packagePath, solution.Source.Path, task)
+		if err != nil {
+			color.Yellow("Skipping %v: %v", solution.Source.Path, err.Error())
+			continue
+		}
+		solutions = append(solutions, p)
+	}
+	return
+}
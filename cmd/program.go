@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/util"
+	"github.com/fatih/color"
+)
+
+// program is a source file together with the template used to compile and run it
+type program struct {
+	// template has the scripts of the profile, base is the template as configured
+	template config.CodeTemplate
+	base     config.CodeTemplate
+	profile  config.BuildProfile
+	path     string
+	full     string
+	file     string
+	task     string
+	rand     string
+	// cached is set when the compilation was skipped thanks to the compilation cache
+	cached bool
+}
+
+func newProgram(filename string, template config.CodeTemplate, task string) *program {
+	path, full := filepath.Split(filename)
+	ext := filepath.Ext(filename)
+	return &program{
+		template: template,
+		base:     template,
+		path:     path,
+		full:     full,
+		file:     full[:len(full)-len(ext)],
+		task:     task,
+		rand:     util.RandString(8),
+	}
+}
+
+func findProgram(filename, task string) (p *program, err error) {
+	cfg := config.Instance
+	filename, index, err := getOneCode(filename, cfg.Template, map[string]struct{}{})
+	if err != nil {
+		return
+	}
+	return newProgram(filename, cfg.Template[index], task), nil
+}
+
+func (p *program) useProfile(name string) (err error) {
+	p.template, p.profile, err = p.base.WithProfile(name)
+	return
+}
+
+// applyProfile turns the memory limit off for the profiles which need it
+func (p *program) applyProfile(options *judge.Options) (err error) {
+	if !p.profile.NoMemoryLimit {
+		return
+	}
+	if options.Oiejq != nil {
+		color.Yellow("oiejq can't run programs without the memory limit, the tests are run without it")
+		options.Oiejq = nil
+		limits, err := judge.ParseLimits(Args.TimeLimit, Args.MemoryLimit)
+		if err != nil {
+			return err
+		}
+		options.Limits = &limits
+	}
+	if options.Limits != nil {
+		limits := *options.Limits
+		limits.NoMemoryLimit = true
+		options.Limits = &limits
+	}
+	if options.Cgroup != nil {
+		cgroup := *options.Cgroup
+		cgroup.Limits.NoMemoryLimit = true
+		options.Cgroup = &cgroup
+	}
+	return
+}
+
+func (p *program) filter(cmd string) string {
+	cmd = strings.ReplaceAll(cmd, "$%rand%$", p.rand)
+	cmd = strings.ReplaceAll(cmd, "$%path%$", p.path)
+	cmd = strings.ReplaceAll(cmd, "$%full%$", p.full)
+	cmd = strings.ReplaceAll(cmd, "$%file%$", p.file)
+	cmd = strings.ReplaceAll(cmd, "$%task%$", p.task)
+	return cmd
+}
+
+func (p *program) run(script string) error {
+	if s := p.filter(script); len(s) > 0 {
+		fmt.Println(s)
+		cmds := util.SplitCmd(s)
+		cmd := exec.Command(cmds[0], cmds[1:]...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+	return nil
+}
+
+func (p *program) compile() error {
+	key, err := p.cacheKey()
+	if err != nil || key == "" {
+		return p.run(p.template.BeforeScript)
+	}
+	if p.restoreFromCache(key) {
+		color.Green("%v is up to date", p.full)
+		p.cached = true
+		return nil
+	}
+	if err = p.run(p.template.BeforeScript); err != nil {
+		return err
+	}
+	p.saveToCache(key)
+	return nil
+}
+
+func (p *program) command() string {
+	return p.filter(p.template.Script)
+}
+
+func (p *program) clean() error {
+	if p.cached {
+		return nil
+	}
+	return p.run(p.template.AfterScript)
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/fatih/color"
+)
+
+const (
+	reportJSON  = "json"
+	reportJUnit = "junit"
+)
+
+const reportStderrLimit = 1024
+
+type testReport struct {
+	ID                string              `json:"id"`
+	Status            judge.VerdictStatus `json:"status"`
+	TimeInSeconds     float64             `json:"time"`
+	MemoryInMegabytes float64             `json:"memory"`
+	Points            float64             `json:"points"`
+	CheckerMessage    string              `json:"checker_message,omitempty"`
+	Error             string              `json:"error,omitempty"`
+	Stderr            string              `json:"stderr,omitempty"`
+	Difference        *judge.Difference   `json:"difference,omitempty"`
+}
+
+type report struct {
+	Task      string                      `json:"task"`
+	TestsRan  int                         `json:"tests_ran"`
+	MaxTime   float64                     `json:"max_time"`
+	MaxMemory float64                     `json:"max_memory"`
+	Points    float64                     `json:"points"`
+	Summary   map[judge.VerdictStatus]int `json:"summary"`
+	Tests     []testReport                `json:"tests"`
+}
+
+var defaultReportFile = map[string]string{
+	reportJSON:  "report.json",
+	reportJUnit: "report.xml",
+}
+
+func checkReportFormat() error {
+	if Args.Report == "" {
+		return nil
+	}
+	if _, ok := defaultReportFile[Args.Report]; !ok {
+		return fmt.Errorf("unknown report format: %v (available: %v, %v)", Args.Report, reportJSON, reportJUnit)
+	}
+	if Args.ReportFile == "" {
+		Args.ReportFile = defaultReportFile[Args.Report]
+	}
+	return nil
+}
+
+func stderrExcerpt(stderr []byte) string {
+	if len(stderr) > reportStderrLimit {
+		return string(stderr[:reportStderrLimit]) + "..."
+	}
+	return string(stderr)
+}
+
+// newReport summarizes the verdicts, which have to be in the order of the tests
+func newReport(task string, testIDs []string, verdicts []judge.Verdict) *report {
+	r := &report{Task: task, Summary: make(map[judge.VerdictStatus]int)}
+	for i, verdict := range verdicts {
+		test := testReport{
+			ID:                testIDs[i],
+			Status:            verdict.Status,
+			TimeInSeconds:     verdict.TimeInSeconds,
+			MemoryInMegabytes: verdict.MemoryInMegabytes,
+			Points:            verdict.Points,
+			CheckerMessage:    verdict.CheckerMessage,
+			Stderr:            stderrExcerpt(verdict.Stderr),
+			Difference:        verdict.Difference,
+		}
+		if verdict.Err != nil {
+			test.Error = verdict.Err.Error()
+		}
+		r.Tests = append(r.Tests, test)
+		r.Summary[verdict.Status]++
+		r.TestsRan++
+		r.MaxTime = math.Max(r.MaxTime, verdict.TimeInSeconds)
+		r.MaxMemory = math.Max(r.MaxMemory, verdict.MemoryInMegabytes)
+		r.Points += verdict.Points
+	}
+	if r.TestsRan > 0 {
+		r.Points /= float64(r.TestsRan)
+	}
+	return r
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	Error      *junitFailure   `xml:"error,omitempty"`
+	SystemErr  string          `xml:"system-err,omitempty"`
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+func (r *report) junit() junitTestSuite {
+	suite := junitTestSuite{Name: r.Task, Tests: r.TestsRan}
+	totalTime := 0.0
+	for _, test := range r.Tests {
+		totalTime += test.TimeInSeconds
+		testCase := junitTestCase{
+			Name:      test.ID,
+			ClassName: r.Task,
+			Time:      fmt.Sprintf("%.3f", test.TimeInSeconds),
+			Properties: []junitProperty{
+				{"status", string(test.Status)},
+				{"memory", fmt.Sprintf("%.3f", test.MemoryInMegabytes)},
+				{"points", fmt.Sprint(test.Points)},
+			},
+			SystemErr: test.Stderr,
+		}
+		text := test.CheckerMessage
+		if test.Difference != nil {
+			text = test.Difference.String()
+		}
+		switch test.Status {
+		case judge.OK:
+		case judge.INT:
+			suite.Errors++
+			testCase.Error = &junitFailure{Message: test.Error, Type: string(test.Status), Text: text}
+		default:
+			suite.Failures++
+			message := string(test.Status)
+			if test.Error != "" {
+				message = test.Error
+			}
+			testCase.Failure = &junitFailure{Message: message, Type: string(test.Status), Text: text}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = fmt.Sprintf("%.3f", totalTime)
+	return suite
+}
+
+// writeReport saves the report in the format given by --report
+func writeReport(r *report) (err error) {
+	var data []byte
+	switch Args.Report {
+	case "":
+		return
+	case reportJSON:
+		data, err = json.MarshalIndent(r, "", "  ")
+	case reportJUnit:
+		data, err = xml.MarshalIndent(r.junit(), "", "  ")
+		data = append([]byte(xml.Header), data...)
+	}
+	if err != nil {
+		return
+	}
+	if err = os.WriteFile(Args.ReportFile, data, 0644); err != nil {
+		return
+	}
+	color.Green("Report saved to %v", Args.ReportFile)
+	return
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Arapak/sio-tool/judge"
+
+	"github.com/fatih/color"
+)
+
+// the generator is retried with these size hints, shrinkSeedsPerSize seeds each
+var shrinkSizes = []int{1, 2, 3, 5, 10, 20, 50, 100}
+
+const shrinkSeedsPerSize = 20
+
+// shrinkMaxRuns bounds the number of inputs checked while shrinking
+const shrinkMaxRuns = 2000
+
+const shrinkShownLength = 1000
+
+// shrinker looks for a smaller input on which the solution still fails
+type shrinker struct {
+	stress          stressRun
+	options         *judge.Options
+	bruteOptions    *judge.Options
+	genScript       string
+	bruteScript     string
+	solveScript     string
+	validatorScript string
+	checker         *judge.Checker
+	comparator      *judge.Comparator
+	runs            int
+}
+
+// valid is true if there is no validator or it accepts the input
+func (s *shrinker) valid(input []byte) bool {
+	if s.validatorScript == "" {
+		return true
+	}
+	processInfo, err := runHelper(s.validatorScript, bytes.NewReader(input))
+	return err == nil && processInfo.Status == judge.OK
+}
+
+// fails is true if the input is valid and the solution fails on it
+func (s *shrinker) fails(input []byte) bool {
+	if s.runs >= shrinkMaxRuns {
+		return false
+	}
+	s.runs++
+	if !s.valid(input) {
+		return false
+	}
+	bruteProcessInfo, err := runBrute(s.bruteOptions, s.bruteScript, input)
+	if err != nil || bruteProcessInfo.Status != judge.OK {
+		return false
+	}
+	solveProcessInfo, _ := s.options.RunOn(s.solveScript, input)
+	if solveProcessInfo.Status != judge.OK {
+		return true
+	}
+	var verdict judge.Verdict
+	if s.checker == nil {
+		verdict = s.comparator.Verdict("", bruteProcessInfo.Output, solveProcessInfo, false)
+	} else {
+		verdict = checkGenerated(s.checker, "", input, bruteProcessInfo.Output, solveProcessInfo)
+	}
+	return verdict.Status != judge.OK && verdict.Status != judge.INT
+}
+
+// shrinkBySize runs the generator with growing size hints and returns the first failing input
+// shorter than the given one
+func (s *shrinker) shrinkBySize(input []byte) []byte {
+	for _, size := range shrinkSizes {
+		for seed := 1; seed <= shrinkSeedsPerSize; seed++ {
+			processInfo, err := s.stress.generate(helperOptions(), s.genScript, strconv.Itoa(seed), strconv.Itoa(size))
+			if err != nil || processInfo.Status != judge.OK {
+				// the generator may not support every size or seed, the other ones are still tried
+				continue
+			}
+			if len(processInfo.Output) < len(input) && s.fails(processInfo.Output) {
+				return processInfo.Output
+			}
+		}
+	}
+	return input
+}
+
+// deltaDebug removes as many of the n parts as it can while the solution keeps failing,
+// it returns the indices of the parts which are left
+func deltaDebug(n int, fails func(kept []int) bool) []int {
+	kept := make([]int, n)
+	for i := range kept {
+		kept[i] = i
+	}
+	granularity := 2
+	for len(kept) >= 2 {
+		size := (len(kept) + granularity - 1) / granularity
+		reduced := false
+		for start := 0; start < len(kept); start += size {
+			end := start + size
+			if end > len(kept) {
+				end = len(kept)
+			}
+			complement := append(append([]int{}, kept[:start]...), kept[end:]...)
+			if fails(complement) {
+				kept = complement
+				if granularity > 2 {
+					granularity--
+				}
+				reduced = true
+				break
+			}
+		}
+		if !reduced {
+			if granularity >= len(kept) {
+				break
+			}
+			granularity *= 2
+			if granularity > len(kept) {
+				granularity = len(kept)
+			}
+		}
+	}
+	return kept
+}
+
+func splitInputLines(input []byte) []string {
+	return strings.Split(strings.TrimRight(string(input), "\n"), "\n")
+}
+
+func (s *shrinker) shrinkLines(input []byte) []byte {
+	lines := splitInputLines(input)
+	join := func(kept []int) []byte {
+		var buf bytes.Buffer
+		for _, i := range kept {
+			buf.WriteString(lines[i] + "\n")
+		}
+		return buf.Bytes()
+	}
+	return join(deltaDebug(len(lines), func(kept []int) bool {
+		return s.fails(join(kept))
+	}))
+}
+
+func (s *shrinker) shrinkTokens(input []byte) []byte {
+	lines := splitInputLines(input)
+	var tokens []string
+	var tokenLines []int
+	for i, line := range lines {
+		for _, token := range strings.Fields(line) {
+			tokens = append(tokens, token)
+			tokenLines = append(tokenLines, i)
+		}
+	}
+	join := func(kept []int) []byte {
+		lineTokens := make([][]string, len(lines))
+		for _, i := range kept {
+			lineTokens[tokenLines[i]] = append(lineTokens[tokenLines[i]], tokens[i])
+		}
+		var buf bytes.Buffer
+		for i, line := range lines {
+			// lines which lost all their tokens are removed
+			if len(lineTokens[i]) == 0 && strings.TrimSpace(line) != "" {
+				continue
+			}
+			buf.WriteString(strings.Join(lineTokens[i], " ") + "\n")
+		}
+		return buf.Bytes()
+	}
+	return join(deltaDebug(len(tokens), func(kept []int) bool {
+		return s.fails(join(kept))
+	}))
+}
+
+// shrink first tries smaller tests from the generator, then, if there is a validator
+// to reject broken inputs, removes lines and tokens of the test
+func (s *shrinker) shrink(input []byte) []byte {
+	minimal := s.shrinkBySize(input)
+	if s.validatorScript == "" {
+		return minimal
+	}
+	minimal = s.shrinkLines(minimal)
+	return s.shrinkTokens(minimal)
+}
+
+func minimalInputPath(input string) string {
+	ext := filepath.Ext(input)
+	return strings.TrimSuffix(input, ext) + "-min" + ext
+}
+
+// shrinkFailing saves the smallest failing input it finds next to the original one and returns it
+func shrinkFailing(s *shrinker, input []byte, inputPath string) []byte {
+	color.Cyan("Shrinking the failing test...")
+	if s.validatorScript == "" {
+		color.Yellow("There is no validator, the test is only shrunk by running the generator with smaller sizes")
+	}
+	minimal := s.shrink(input)
+	if len(minimal) >= len(input) {
+		color.Yellow("Cannot find a smaller failing test")
+		return input
+	}
+	path := minimalInputPath(inputPath)
+	if err := os.WriteFile(path, minimal, 0644); err != nil {
+		color.Red(err.Error())
+		return input
+	}
+	color.Green("Minimal failing test (%v bytes, the original has %v) saved to %v", len(minimal), len(input), path)
+	if len(minimal) <= shrinkShownLength {
+		fmt.Print(string(minimal))
+	}
+	return minimal
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeltaDebug(t *testing.T) {
+	contains := func(kept []int, parts ...int) bool {
+		found := 0
+		for _, i := range kept {
+			for _, part := range parts {
+				if i == part {
+					found++
+				}
+			}
+		}
+		return found == len(parts)
+	}
+	tests := []struct {
+		n      int
+		fails  func(kept []int) bool
+		expect []int
+	}{
+		{8, func(kept []int) bool { return contains(kept, 5) }, []int{5}},
+		{8, func(kept []int) bool { return contains(kept, 1, 6) }, []int{1, 6}},
+		{5, func(kept []int) bool { return contains(kept, 0, 2, 4) }, []int{0, 2, 4}},
+		{4, func(kept []int) bool { return len(kept) == 4 }, []int{0, 1, 2, 3}},
+		{1, func(kept []int) bool { return true }, []int{0}},
+		{3, func(kept []int) bool { return true }, []int{2}},
+	}
+	for _, test := range tests {
+		kept := deltaDebug(test.n, test.fails)
+		if !reflect.DeepEqual(kept, test.expect) {
+			t.Errorf("Expect %v, but found %v.", test.expect, kept)
+		}
+	}
+}
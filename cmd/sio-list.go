@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/Arapak/sio-tool/metadata"
+	"github.com/Arapak/sio-tool/sio_client"
 	"github.com/Arapak/sio-tool/util"
 	"github.com/k0kubun/go-ansi"
 	"github.com/olekukonko/tablewriter"
@@ -34,7 +36,7 @@ func SioList() (err error) {
 	var buf bytes.Buffer
 	output := io.Writer(&buf)
 	table := tablewriter.NewWriter(output)
-	table.SetHeader([]string{"round", "name", "alias", "points"})
+	table.SetHeader([]string{"round", "name", "alias", "points", "limits"})
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetAlignment(tablewriter.ALIGN_CENTER)
 	table.SetCenterSeparator("|")
@@ -45,6 +47,7 @@ func SioList() (err error) {
 			util.LimitNumOfChars(prob.Name, 25),
 			prob.Alias,
 			prob.ParsePoint(),
+			parsedLimits((&sio_client.Info{RootPath: info.RootPath, Contest: info.Contest, Round: prob.Round, ProblemAlias: prob.Alias}).Path()),
 		})
 	}
 	table.Render()
@@ -57,6 +60,15 @@ func SioList() (err error) {
 	return
 }
 
+// parsedLimits shows the limits saved in the metadata of the problem in path, if it was parsed
+func parsedLimits(path string) string {
+	problem, err := metadata.Load(path)
+	if err != nil || (problem.TimeLimitInSeconds == 0 && problem.MemoryLimitInMegabytes == 0) {
+		return ""
+	}
+	return problem.Limits().String()
+}
+
 func SioListContests() (err error) {
 	cln := getSioClient()
 	err = cln.Ping()
@@ -9,6 +9,7 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/metadata"
 )
 
 func SioParse() (err error) {
@@ -40,7 +41,20 @@ func SioParse() (err error) {
 	defer db.Close()
 
 	work := func() error {
-		_, paths, err := cln.Parse(info, db)
+		parsed, paths, err := cln.Parse(info, db)
+		for _, problem := range parsed {
+			m := &metadata.Problem{
+				Name:                   problem.Name,
+				Link:                   problem.Link,
+				Sio:                    &problem.Info,
+				TimeLimitInSeconds:     problem.Limits.TimeLimitInSeconds,
+				MemoryLimitInMegabytes: problem.Limits.MemoryLimitInMegabytes,
+				IO:                     metadata.IOMode(problem.StandardIO),
+			}
+			if err := m.Update(problem.Path); err != nil {
+				color.Red(err.Error())
+			}
+		}
 		if cfg.GenAfterParse {
 			for _, path := range paths {
 				err = GenFiles(source, path, ext)
@@ -6,13 +6,17 @@ import (
 )
 
 func SioUploadPackage() (err error) {
+	return uploadPackage(Args.File)
+}
+
+// uploadPackage uploads the package archive as the new package of the problem given in the arguments
+func uploadPackage(file string) (err error) {
 	cln := getSioClient()
 	err = cln.Ping()
 	if err != nil {
 		return
 	}
 	info := Args.SioInfo
-	file := Args.File
 
 	var rootPath string
 	if !path.IsAbs(file) {
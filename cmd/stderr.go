@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Arapak/sio-tool/judge"
+
+	"github.com/fatih/color"
+)
+
+// only the beginning of stderr is shown, the rest is in the saved file
+const stderrShownLines = 10
+const stderrShownLineLength = 160
+
+// stderrPath is the file next to the input where the stderr of the failing test is saved
+func stderrPath(input string) string {
+	return strings.TrimSuffix(input, filepath.Ext(input)) + ".stderr"
+}
+
+func showStderr(verdict judge.Verdict) bool {
+	return len(verdict.Stderr) > 0 && (verdict.Status != judge.OK || Args.Stderr)
+}
+
+// printStderr shows the beginning of what the program printed to stderr
+func printStderr(stderr []byte, savedTo string) {
+	color.Cyan("-----Stderr-----")
+	lines := strings.Split(strings.TrimRight(string(stderr), "\n"), "\n")
+	for i, line := range lines {
+		if i == stderrShownLines {
+			fmt.Printf("... (%v more lines)\n", len(lines)-stderrShownLines)
+			break
+		}
+		if len(line) > stderrShownLineLength {
+			line = line[:stderrShownLineLength] + "..."
+		}
+		fmt.Println(line)
+	}
+	if savedTo != "" {
+		color.Cyan("full stderr saved to %v", savedTo)
+	}
+}
+
+// saveStderr writes the full stderr of the failing test next to its input
+func saveStderr(verdict judge.Verdict, input string) (path string) {
+	if verdict.Status == judge.OK {
+		return
+	}
+	return writeStderr(verdict.Stderr, input)
+}
+
+func writeStderr(stderr []byte, input string) (path string) {
+	if len(stderr) == 0 {
+		return
+	}
+	path = stderrPath(input)
+	if err := os.WriteFile(path, stderr, 0644); err != nil {
+		color.Red("Failed to save stderr: %v", err.Error())
+		return ""
+	}
+	return
+}
+
+// printVerdictWithStderr prints the verdict and below it the stderr of the program,
+// for the failing tests (saving it next to the input) or for all tests with --stderr
+func printVerdictWithStderr(verdict judge.Verdict, testID string, input string) {
+	printVerdict(verdict, testID)
+	savedTo := saveStderr(verdict, input)
+	if showStderr(verdict) {
+		printStderr(verdict.Stderr, savedTo)
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/fatih/color"
+	"github.com/k0kubun/go-ansi"
+)
+
+const (
+	maximizeTime   = "time"
+	maximizeMemory = "memory"
+)
+
+const defaultMaximizeTop = 5
+
+// without --iterations and --duration the worst case is searched among this many seeds
+const defaultMaximizeIterations = 1000
+
+type worstCase struct {
+	seed              int
+	status            judge.VerdictStatus
+	timeInSeconds     float64
+	memoryInMegabytes float64
+	path              string
+}
+
+func (w worstCase) value(maximize string) float64 {
+	if maximize == maximizeMemory {
+		return w.memoryInMegabytes
+	}
+	return w.timeInSeconds
+}
+
+// worstCases keeps the top inputs on disk, removing the ones which drop out of the top
+type worstCases struct {
+	maximize string
+	top      int
+	cases    []worstCase
+}
+
+func (w *worstCases) add(c worstCase, input []byte) {
+	if len(w.cases) == w.top && c.value(w.maximize) <= w.cases[len(w.cases)-1].value(w.maximize) {
+		return
+	}
+	if err := os.WriteFile(c.path, input, 0644); err != nil {
+		color.Red(err.Error())
+		return
+	}
+	w.cases = append(w.cases, c)
+	sort.SliceStable(w.cases, func(i, j int) bool {
+		return w.cases[i].value(w.maximize) > w.cases[j].value(w.maximize)
+	})
+	if len(w.cases) > w.top {
+		_ = os.Remove(w.cases[w.top].path)
+		w.cases = w.cases[:w.top]
+	}
+}
+
+func (w *worstCases) worst() float64 {
+	if len(w.cases) == 0 {
+		return 0
+	}
+	return w.cases[0].value(w.maximize)
+}
+
+func (w *worstCases) print() {
+	var buf bytes.Buffer
+	table := newTable(&buf, []string{"#", "seed", "verdict", "time", "memory", "file"})
+	for i, c := range w.cases {
+		status := util.GreenString(string(c.status))
+		if c.status != judge.OK {
+			status = util.RedString(string(c.status))
+		}
+		table.Append([]string{
+			fmt.Sprint(i + 1),
+			fmt.Sprint(c.seed),
+			status,
+			fmt.Sprintf("%.3fs", c.timeInSeconds),
+			judge.ParseMemory(c.memoryInMegabytes),
+			c.path,
+		})
+	}
+	table.Render()
+	printTable(&buf)
+}
+
+func maximizeOptions() (top int, err error) {
+	if Args.Maximize != maximizeTime && Args.Maximize != maximizeMemory {
+		return 0, fmt.Errorf("unknown value of --maximize: %v (available: %v, %v)", Args.Maximize, maximizeTime, maximizeMemory)
+	}
+	return parsePositive("--top", Args.Top, defaultMaximizeTop)
+}
+
+// stressMaximize runs the solution on many generated tests and keeps the ones
+// on which it is the slowest or uses the most memory
+func stressMaximize(stress stressRun, options *judge.Options, testsGenScript, solveScript, testInFormat string) (err error) {
+	top, err := maximizeOptions()
+	if err != nil {
+		return
+	}
+	if !stress.limited() {
+		stress.iterations = defaultMaximizeIterations
+	}
+	worst := &worstCases{maximize: Args.Maximize, top: top}
+
+	wg := sync.WaitGroup{}
+	wg.Add(stress.workers)
+	mu := sync.Mutex{}
+
+	stop := false
+	currentTestNumber := stress.seedStart
+	testsRan := 0
+	start := time.Now()
+
+	for i := 1; i <= stress.workers; i++ {
+		go func(workerID int) {
+			defer wg.Done()
+			for {
+				mu.Lock()
+				if stop || stress.finished(currentTestNumber, start) {
+					mu.Unlock()
+					return
+				}
+				testNumber := currentTestNumber
+				currentTestNumber++
+				mu.Unlock()
+				testID := strconv.Itoa(testNumber)
+				genProcessInfo, err := stress.generate(helperOptions(), testsGenScript, testID)
+
+				if genProcessInfo.Status != judge.OK {
+					mu.Lock()
+					clearProgress()
+					if err == nil {
+						color.Red("#%v GEN - %v", testID, string(genProcessInfo.Status))
+					} else {
+						color.Red("#%v GEN - %v: %v", testID, string(genProcessInfo.Status), err.Error())
+					}
+					stop = true
+					mu.Unlock()
+					return
+				}
+
+				solveProcessInfo, err := options.RunOn(solveScript, genProcessInfo.Output)
+
+				mu.Lock()
+				testsRan++
+				limitExceeded := solveProcessInfo.Status == judge.TLE || solveProcessInfo.Status == judge.MLE
+				if solveProcessInfo.Status != judge.OK && !limitExceeded {
+					clearProgress()
+					if err == nil {
+						color.Red("#%v SOLVE - %v", testID, string(solveProcessInfo.Status))
+					} else {
+						color.Red("#%v SOLVE - %v: %v", testID, string(solveProcessInfo.Status), err.Error())
+					}
+				}
+				worst.add(worstCase{
+					seed:              testNumber,
+					status:            solveProcessInfo.Status,
+					timeInSeconds:     solveProcessInfo.TimeInSeconds,
+					memoryInMegabytes: solveProcessInfo.MemoryInMegabytes,
+					path:              strings.ReplaceAll(testInFormat, "$%test%$", testID),
+				}, genProcessInfo.Output)
+				if limitExceeded && Args.StopOnLimit {
+					clearProgress()
+					color.Yellow("#%v SOLVE - %v", testID, string(solveProcessInfo.Status))
+					stop = true
+				}
+				printStressProgress(testsRan, time.Since(start))
+				if Args.Maximize == maximizeMemory {
+					_, _ = ansi.Printf(" MAX MEMORY: %v", judge.ParseMemory(worst.worst()))
+				} else {
+					_, _ = ansi.Printf(" MAX TIME: %.3fs", worst.worst())
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	color.Blue("\n----FINISHED----")
+	worst.print()
+	return
+}
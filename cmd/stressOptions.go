@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/k0kubun/go-ansi"
+)
+
+const defaultStressWorkers = 10
+
+// stressRun describes which seeds are tested and for how long,
+// by default from seed 1 until the first failing test
+type stressRun struct {
+	seedStart  int
+	iterations int
+	duration   time.Duration
+	workers    int
+	seedArg    bool
+}
+
+func parsePositive(name, value string, defaultValue int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		return 0, fmt.Errorf("%v has to be a positive integer, found %v", name, value)
+	}
+	return number, nil
+}
+
+// parseStressDuration accepts Go durations like "10m" or "1h30m" and plain numbers of seconds
+func parseStressDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration: %v (e.g. 90, 30s or 10m)", value)
+	}
+	return duration, nil
+}
+
+func stressOptions() (stress stressRun, err error) {
+	if Args.SeedStart == "" {
+		stress.seedStart = 1
+	} else if stress.seedStart, err = strconv.Atoi(Args.SeedStart); err != nil {
+		return stress, fmt.Errorf("invalid seed: %v", Args.SeedStart)
+	}
+	if stress.iterations, err = parsePositive("--iterations", Args.Iterations, 0); err != nil {
+		return
+	}
+	if stress.workers, err = parsePositive("--workers", Args.Workers, defaultStressWorkers); err != nil {
+		return
+	}
+	stress.duration, err = parseStressDuration(Args.Duration)
+	stress.seedArg = Args.SeedArg
+	return
+}
+
+// limited is true if the run stops after some number of tests or time, not only on a failing test
+func (stress stressRun) limited() bool {
+	return stress.iterations > 0 || stress.duration > 0
+}
+
+func (stress stressRun) finished(nextSeed int, start time.Time) bool {
+	if stress.iterations > 0 && nextSeed >= stress.seedStart+stress.iterations {
+		return true
+	}
+	return stress.duration > 0 && time.Since(start) >= stress.duration
+}
+
+// generate runs the generator with the seed (and other arguments like the size hint)
+// on the standard input or, with --seed-arg, as its arguments
+func (stress stressRun) generate(options *judge.Options, script string, args ...string) (judge.ProcessInfo, error) {
+	if stress.seedArg {
+		return options.Run(script+" "+strings.Join(args, " "), strings.NewReader(""))
+	}
+	return options.Run(script, strings.NewReader(strings.Join(args, " ")))
+}
+
+func clearProgress() {
+	ansi.EraseInLine(2)
+	ansi.CursorHorizontalAbsolute(0)
+}
+
+func printStressProgress(testsRan int, elapsed time.Duration) {
+	clearProgress()
+	speed := 0.0
+	if elapsed > 0 {
+		speed = float64(testsRan) / elapsed.Seconds()
+	}
+	_, _ = ansi.Printf("TESTS RAN: %v (%.1f tests/s, %v)", util.BlueString(fmt.Sprint(testsRan)), speed, elapsed.Round(time.Second))
+}
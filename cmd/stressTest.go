@@ -1,19 +1,16 @@
 package cmd
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Arapak/sio-tool/config"
 	"github.com/Arapak/sio-tool/judge"
-	"github.com/Arapak/sio-tool/util"
 
 	"github.com/fatih/color"
 )
@@ -23,11 +20,19 @@ func StressTest() (err error) {
 	if len(cfg.Template) == 0 {
 		return errors.New("you have to add at least one code template by `st config`")
 	}
-	if len(cfg.DefaultNaming) == 0 || cfg.DefaultNaming["solve"] == "" || cfg.DefaultNaming["brute"] == "" || cfg.DefaultNaming["gen"] == "" || cfg.DefaultNaming["test_in"] == "" {
+	if len(cfg.DefaultNaming) == 0 || cfg.DefaultNaming["solve"] == "" || cfg.DefaultNaming["gen"] == "" || cfg.DefaultNaming["test_in"] == "" {
 		return errors.New("you have to add default naming by `st config`")
 	}
 
+	if Args.Maximize == "" && (Args.Top != "" || Args.StopOnLimit) {
+		return errors.New("--top and --stop-on-limit can only be used with --maximize")
+	}
+
 	task := Args.Specifier[0]
+	problem, err := loadProblemDefaults()
+	if err != nil {
+		return
+	}
 
 	solveFilePattern := cfg.DefaultNaming["solve"]
 	if Args.Solve != "" {
@@ -35,27 +40,13 @@ func StressTest() (err error) {
 	} else {
 		solveFilePattern = strings.ReplaceAll(solveFilePattern, "$%task%$", task)
 	}
-	solveFilename, index, err := getOneCode(solveFilePattern, cfg.Template, map[string]struct{}{})
+	solve, err := findProgram(solveFilePattern, task)
 	if err != nil {
 		return
 	}
-	solvePath, solveFull := filepath.Split(solveFilename)
-	ext := filepath.Ext(solveFilename)
-	solveFile := solveFull[:len(solveFull)-len(ext)]
-
-	bruteFilePattern := cfg.DefaultNaming["brute"]
-	if Args.Brute != "" {
-		bruteFilePattern = Args.Brute
-	} else {
-		bruteFilePattern = strings.ReplaceAll(bruteFilePattern, "$%task%$", task)
-	}
-	bruteFilename, _, err := getOneCode(bruteFilePattern, cfg.Template, map[string]struct{}{})
-	if err != nil {
+	if err = solve.useProfile(Args.Profile); err != nil {
 		return
 	}
-	brutePath, bruteFull := filepath.Split(bruteFilename)
-	ext = filepath.Ext(bruteFilename)
-	bruteFile := bruteFull[:len(bruteFull)-len(ext)]
 
 	testsGenFilePattern := cfg.DefaultNaming["gen"]
 	if Args.Generator != "" {
@@ -63,77 +54,86 @@ func StressTest() (err error) {
 	} else {
 		testsGenFilePattern = strings.ReplaceAll(testsGenFilePattern, "$%task%$", task)
 	}
-	testsGenFilename, _, err := getOneCode(testsGenFilePattern, cfg.Template, map[string]struct{}{})
+	testsGen, err := findProgram(testsGenFilePattern, task)
 	if err != nil {
 		return
 	}
-	testsGenPath, testsGenFull := filepath.Split(testsGenFilename)
-	ext = filepath.Ext(testsGenFilename)
-	testsGenFile := testsGenFull[:len(testsGenFull)-len(ext)]
-
-	template := cfg.Template[index]
-	rand := util.RandString(8)
-
-	filter := func(cmd, path, full, file string) string {
-		cmd = strings.ReplaceAll(cmd, "$%rand%$", rand)
-		cmd = strings.ReplaceAll(cmd, "$%path%$", path)
-		cmd = strings.ReplaceAll(cmd, "$%full%$", full)
-		cmd = strings.ReplaceAll(cmd, "$%file%$", file)
-		cmd = strings.ReplaceAll(cmd, "$%task%$", task)
-		return cmd
-	}
-
-	run := func(script, path, full, file string) error {
-		if s := filter(script, path, full, file); len(s) > 0 {
-			fmt.Println(s)
-			cmds := util.SplitCmd(s)
-			cmd := exec.Command(cmds[0], cmds[1:]...)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
-		}
-		return nil
-	}
 
-	if err = run(template.BeforeScript, solvePath, solveFull, solveFile); err != nil {
+	if err = solve.compile(); err != nil {
 		return
 	}
-	if err = run(template.BeforeScript, brutePath, bruteFull, bruteFile); err != nil {
+	if err = testsGen.compile(); err != nil {
 		return
 	}
-	if err = run(template.BeforeScript, testsGenPath, testsGenFull, testsGenFile); err != nil {
-		return
-	}
-
-	solveScript := filter(template.Script, solvePath, solveFull, solveFile)
-	bruteScript := filter(template.Script, brutePath, bruteFull, bruteFile)
-	testsGenScript := filter(template.Script, testsGenPath, testsGenFull, testsGenFile)
 
-	if len(solveScript) == 0 || len(bruteScript) == 0 || len(testsGenScript) == 0 {
-		return errors.New("invalid script command. Please check config file")
+	solveScript := solve.command()
+	testsGenScript := testsGen.command()
+	if len(solveScript) == 0 || len(testsGenScript) == 0 {
+		return errors.New(ErrorInvalidScript)
 	}
 
 	testInFormat := strings.ReplaceAll(cfg.DefaultNaming["test_in"], "$%task%$", task)
 
-	numberOfWorkers := 10
+	stress, err := stressOptions()
+	if err != nil {
+		return
+	}
+	options, err := runOptions()
+	if err != nil {
+		return
+	}
+	if err = solve.applyProfile(options); err != nil {
+		return
+	}
+	options.FileIO = problemFileIO(problem)
+	// maximizing only runs the generator and the solution
+	if Args.Maximize != "" {
+		return stressMaximize(stress, options, testsGenScript, solveScript, testInFormat)
+	}
+
+	checker, _, err := findChecker(task)
+	if err != nil {
+		return
+	}
+	// with a checker the brute force solution is optional, its output is only passed to the checker as the answer
+	brute, err := findOptionalProgram(Args.Brute, "brute", task)
+	if err != nil {
+		return
+	}
+	if brute == nil && checker == nil {
+		return errors.New("cannot find the brute force solution, you need it or a checker to stress test")
+	}
+	validator, err := findOptionalProgram(Args.Validator, "validator", task)
+	if err != nil {
+		return
+	}
+	bruteScript := ""
+	if brute != nil {
+		bruteScript = brute.command()
+	}
+	// only the solution is judged with the limits of the problem, the brute force solution is a helper
+	bruteOptions := helperOptions()
+	bruteOptions.FileIO = options.FileIO
+	comparator, err := findComparator()
+	if err != nil {
+		return
+	}
 
 	wg := sync.WaitGroup{}
-	wg.Add(numberOfWorkers)
+	wg.Add(stress.workers)
 	mu := sync.Mutex{}
 
 	workerError := false
-	currentTestNumber := 1
+	// set when the generator or the brute force solution fails, the seeds weren't all checked then
+	aborted := false
+	currentTestNumber := stress.seedStart
+	testsRan := 0
+	start := time.Now()
+	// the first test on which the solution failed, to re-run it with --debug_failing
+	var failedTestID string
+	var failedInput []byte
 
-	var oiejqOptions *judge.OiejqOptions
-	if Args.Oiejq {
-		err = judge.InstallSio2Jail()
-		if err != nil {
-			return
-		}
-		oiejqOptions = &judge.OiejqOptions{MemorylimitInMegaBytes: Args.MemoryLimit, TimeLimitInSeconds: Args.TimeLimit}
-	}
-
-	for i := 1; i <= numberOfWorkers; i++ {
+	for i := 1; i <= stress.workers; i++ {
 		go func(workerID int) {
 			defer func() {
 				mu.Lock()
@@ -143,7 +143,7 @@ func StressTest() (err error) {
 			}()
 			for {
 				mu.Lock()
-				if workerError {
+				if workerError || stress.finished(currentTestNumber, start) {
 					mu.Unlock()
 					return
 				}
@@ -151,33 +151,27 @@ func StressTest() (err error) {
 				currentTestNumber++
 				mu.Unlock()
 				testID := strconv.Itoa(testNumber)
-				var genProcessInfo judge.ProcessInfo
-				if oiejqOptions == nil {
-					genProcessInfo, err = judge.RunProcess(testsGenScript, strings.NewReader(testID), nil)
-				} else {
-					genProcessInfo, err = judge.RunProcessWithOiejq(testsGenScript, strings.NewReader(testID), oiejqOptions)
-				}
+				genProcessInfo, err := stress.generate(helperOptions(), testsGenScript, testID)
 
 				if genProcessInfo.Status != judge.OK {
 					mu.Lock()
+					aborted = true
+					clearProgress()
 					if err == nil {
 						color.Red("#%v GEN - %v", testID, string(genProcessInfo.Status))
 					} else {
 						color.Red("#%v GEN - %v: %v", testID, string(genProcessInfo.Status), err.Error())
 					}
-					mu.Lock()
+					mu.Unlock()
 					return
 				}
 
-				var bruteProcessInfo judge.ProcessInfo
-				if oiejqOptions == nil {
-					bruteProcessInfo, err = judge.RunProcess(bruteScript, bytes.NewReader(genProcessInfo.Output), nil)
-				} else {
-					bruteProcessInfo, err = judge.RunProcessWithOiejq(bruteScript, bytes.NewReader(genProcessInfo.Output), oiejqOptions)
-				}
+				bruteProcessInfo, err := runBrute(bruteOptions, bruteScript, genProcessInfo.Output)
 
 				if bruteProcessInfo.Status != judge.OK {
 					mu.Lock()
+					aborted = true
+					clearProgress()
 					if err == nil {
 						color.Red("#%v BRUTE - %v", testID, string(bruteProcessInfo.Status))
 					} else {
@@ -187,25 +181,42 @@ func StressTest() (err error) {
 					return
 				}
 
-				var solveProcessInfo judge.ProcessInfo
-				if oiejqOptions == nil {
-					solveProcessInfo, err = judge.RunProcess(solveScript, bytes.NewReader(genProcessInfo.Output), nil)
-				} else {
-					solveProcessInfo, err = judge.RunProcessWithOiejq(solveScript, bytes.NewReader(genProcessInfo.Output), oiejqOptions)
-				}
+				solveProcessInfo, err := options.RunOn(solveScript, genProcessInfo.Output)
 
 				if solveProcessInfo.Status != judge.OK {
 					mu.Lock()
+					if workerError {
+						mu.Unlock()
+						return
+					}
+					workerError = true
+					testsRan++
+					if failedTestID == "" {
+						failedTestID, failedInput = testID, genProcessInfo.Output
+					}
+					clearProgress()
 					if err == nil {
 						color.Red("#%v SOLVE - %v", testID, string(solveProcessInfo.Status))
 					} else {
 						color.Red("#%v SOLVE - %v: %v", testID, string(solveProcessInfo.Status), err.Error())
 					}
+					input := strings.ReplaceAll(testInFormat, "$%test%$", testID)
+					if err = os.WriteFile(input, genProcessInfo.Output, 0644); err != nil {
+						color.Red(err.Error())
+					}
+					if len(solveProcessInfo.Stderr) > 0 {
+						printStderr(solveProcessInfo.Stderr, writeStderr(solveProcessInfo.Stderr, input))
+					}
 					mu.Unlock()
 					return
 				}
 
-				verdict := judge.GenerateVerdict(testID, judge.Plain(bruteProcessInfo.Output), solveProcessInfo)
+				var verdict judge.Verdict
+				if checker == nil {
+					verdict = comparator.Verdict(testID, bruteProcessInfo.Output, solveProcessInfo, options.SideBySide)
+				} else {
+					verdict = checkGenerated(checker, testID, genProcessInfo.Output, bruteProcessInfo.Output, solveProcessInfo)
+				}
 				if verdict.Status != judge.OK {
 					mu.Lock()
 					if workerError {
@@ -213,21 +224,97 @@ func StressTest() (err error) {
 						return
 					}
 					workerError = true
-					fmt.Print(verdict.Message)
-					err = os.WriteFile(strings.ReplaceAll(testInFormat, "$%test%$", testID), genProcessInfo.Output, 0644)
+					testsRan++
+					if failedTestID == "" {
+						failedTestID, failedInput = testID, genProcessInfo.Output
+					}
+					clearProgress()
+					if verdict.Err != nil {
+						color.Red("#%v CHECKER - %v", testID, verdict.Err.Error())
+					} else {
+						fmt.Print(verdict.Message)
+					}
+					input := strings.ReplaceAll(testInFormat, "$%test%$", testID)
+					err = os.WriteFile(input, genProcessInfo.Output, 0644)
 					if err != nil {
 						color.Red(err.Error())
 					}
+					verdict.Stderr = solveProcessInfo.Stderr
+					if showStderr(verdict) {
+						printStderr(verdict.Stderr, saveStderr(verdict, input))
+					}
 					mu.Unlock()
 					return
 				}
 				mu.Lock()
-				fmt.Print(verdict.Message)
+				testsRan++
+				if showStderr(verdict) {
+					clearProgress()
+					fmt.Print(verdict.Message)
+					printStderr(verdict.Stderr, "")
+				}
+				printStressProgress(testsRan, time.Since(start))
 				mu.Unlock()
 			}
 		}(i)
 	}
 	wg.Wait()
-	color.Blue("----FINISHED----")
+	clearProgress()
+	printStressProgress(testsRan, time.Since(start))
+	color.Blue("\n----FINISHED----")
+	if failedTestID == "" && !aborted && stress.limited() {
+		color.Green("No failing test found (seeds %v-%v)", stress.seedStart, stress.seedStart+testsRan-1)
+	}
+	if failedTestID != "" && !Args.NoShrink {
+		s := &shrinker{
+			stress:       stress,
+			options:      options,
+			bruteOptions: bruteOptions,
+			genScript:    testsGenScript,
+			bruteScript:  bruteScript,
+			solveScript:  solveScript,
+			checker:      checker,
+			comparator:   comparator,
+		}
+		if validator != nil {
+			s.validatorScript = validator.command()
+		}
+		failedInput = shrinkFailing(s, failedInput, strings.ReplaceAll(testInFormat, "$%test%$", failedTestID))
+	}
+	if Args.DebugFailing && failedTestID != "" {
+		return rerunWithDebugProfile(solve, failedTestID, failedInput)
+	}
 	return
 }
+
+// runBrute returns the answer of the brute force solution, which is empty if there is none
+func runBrute(options *judge.Options, bruteScript string, input []byte) (judge.ProcessInfo, error) {
+	if bruteScript == "" {
+		return judge.ProcessInfo{Status: judge.OK}, nil
+	}
+	return options.RunOn(bruteScript, input)
+}
+
+func checkGenerated(checker *judge.Checker, testID string, input, answer []byte, processInfo judge.ProcessInfo) judge.Verdict {
+	inFile, err := os.CreateTemp(os.TempDir(), "st-input-")
+	if err != nil {
+		return judge.Verdict{Status: judge.INT, Err: err}
+	}
+	defer os.Remove(inFile.Name())
+	ansFile, err := os.CreateTemp(os.TempDir(), "st-answer-")
+	if err != nil {
+		inFile.Close()
+		return judge.Verdict{Status: judge.INT, Err: err}
+	}
+	defer os.Remove(ansFile.Name())
+	_, err = inFile.Write(input)
+	inFile.Close()
+	if err == nil {
+		_, err = ansFile.Write(answer)
+	}
+	ansFile.Close()
+	if err != nil {
+		return judge.Verdict{Status: judge.INT, Err: err}
+	}
+	return checker.Verdict(testID, inFile.Name(), ansFile.Name(), processInfo)
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/sinol_package"
+	"github.com/Arapak/sio-tool/util"
+
+	"github.com/k0kubun/go-ansi"
+	"github.com/olekukonko/tablewriter"
+)
+
+type subtask struct {
+	group     int
+	maxPoints float64
+	tests     int
+	status    judge.VerdictStatus
+	minTime   float64
+	maxTime   float64
+	// oiPoints follows the all-or-nothing rule, sioPoints also the time-based scoring
+	oiPoints  float64
+	sioPoints float64
+	// sioUnknown is set when some test has no known time limit to score it by
+	sioUnknown bool
+}
+
+// groupTests assigns every test to its subtask, ok is false if some test name doesn't follow the OI naming
+func groupTests(task string, tests []string) (groups map[int][]int, ok bool) {
+	groups = make(map[int][]int)
+	for i, test := range tests {
+		group, ok := sinol_package.TestGroup(task, test)
+		if !ok {
+			return nil, false
+		}
+		groups[group] = append(groups[group], i)
+	}
+	return groups, true
+}
+
+// scoringTimeLimit is the time limit used for the time-based scoring, 0 if none is known
+func scoringTimeLimit(config *sinol_package.Config) float64 {
+	if Args.TimeLimit == "" {
+		if config != nil && config.TimeLimit > 0 {
+			return float64(config.TimeLimit) / 1000
+		}
+		return 0
+	}
+	limits, err := judge.ParseLimits(Args.TimeLimit, "")
+	if err != nil {
+		return 0
+	}
+	return limits.TimeLimitInSeconds
+}
+
+func newSubtasks(groups map[int][]int, scores map[int]float64, verdicts []judge.Verdict, timeLimits []float64) (subtasks []subtask) {
+	for group, tests := range groups {
+		s := subtask{group: group, maxPoints: scores[group], tests: len(tests), status: judge.OK, minTime: math.Inf(1)}
+		oiScore, sioScore := 1.0, 1.0
+		for _, i := range tests {
+			verdict := verdicts[i]
+			if s.status == judge.OK && verdict.Status != judge.OK {
+				s.status = verdict.Status
+			}
+			s.minTime = math.Min(s.minTime, verdict.TimeInSeconds)
+			s.maxTime = math.Max(s.maxTime, verdict.TimeInSeconds)
+			if timeLimits[i] <= 0 {
+				s.sioUnknown = true
+			}
+			if verdict.Status != judge.OK {
+				oiScore, sioScore = 0, 0
+				continue
+			}
+			oiScore = math.Min(oiScore, verdict.Points/100)
+			sioScore = math.Min(sioScore, sinol_package.TestScore(verdict.Points, verdict.TimeInSeconds, timeLimits[i]))
+		}
+		if oiScore < 1 {
+			oiScore = 0
+		}
+		s.oiPoints = oiScore * s.maxPoints
+		s.sioPoints = sioScore * s.maxPoints
+		subtasks = append(subtasks, s)
+	}
+	sort.Slice(subtasks, func(i, j int) bool {
+		return subtasks[i].group < subtasks[j].group
+	})
+	return
+}
+
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+// printSubtasks shows the verdict and the points of every subtask, if the tests are named like in OI
+func printSubtasks(task string, config *sinol_package.Config, tests []string, verdicts []judge.Verdict) {
+	groups, ok := groupTests(task, tests)
+	if !ok {
+		return
+	}
+	var groupIDs []int
+	for group := range groups {
+		groupIDs = append(groupIDs, group)
+	}
+	subtasks := newSubtasks(groups, sinol_package.Scores(config, groupIDs), verdicts, testTimeLimits(task, config, tests))
+
+	var buf bytes.Buffer
+	table := tablewriter.NewWriter(io.Writer(&buf))
+	table.SetHeader([]string{"group", "tests", "verdict", "min time", "max time", "OI points", "Sio points"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	oiTotal, sioTotal, maxTotal := 0.0, 0.0, 0.0
+	sioUnknown := false
+	for _, s := range subtasks {
+		status := util.GreenString(string(s.status))
+		if s.status != judge.OK {
+			status = util.RedString(string(s.status))
+		}
+		sioPoints := fmt.Sprintf("%.2f/%v", s.sioPoints, formatPoints(s.maxPoints))
+		if s.sioUnknown {
+			sioPoints = "?"
+			sioUnknown = true
+		}
+		table.Append([]string{
+			fmt.Sprint(s.group),
+			fmt.Sprint(s.tests),
+			status,
+			fmt.Sprintf("%.3fs", s.minTime),
+			fmt.Sprintf("%.3fs", s.maxTime),
+			fmt.Sprintf("%v/%v", formatPoints(s.oiPoints), formatPoints(s.maxPoints)),
+			sioPoints,
+		})
+		oiTotal += s.oiPoints
+		sioTotal += s.sioPoints
+		maxTotal += s.maxPoints
+	}
+	table.Render()
+	scanner := bufio.NewScanner(io.Reader(&buf))
+	for scanner.Scan() {
+		_, _ = ansi.Println(scanner.Text())
+	}
+	if sioUnknown {
+		// the Sio points depend on the time limit, without it only the OI score is shown
+		_, _ = ansi.Printf("SCORE: %v/%v (OI), the Sio score needs the time limit\n", util.BlueString(formatPoints(oiTotal)), formatPoints(maxTotal))
+		return
+	}
+	_, _ = ansi.Printf("SCORE: %v/%v (OI), %.2f/%v (Sio)\n", util.BlueString(formatPoints(oiTotal)), formatPoints(maxTotal), sioTotal, formatPoints(maxTotal))
+}
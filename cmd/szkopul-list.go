@@ -31,7 +31,7 @@ func SzkopulList() (err error) {
 	var buf bytes.Buffer
 	output := io.Writer(&buf)
 	table := tablewriter.NewWriter(output)
-	table.SetHeader([]string{"contest", "stage", "name", "alias", "points"})
+	table.SetHeader([]string{"contest", "stage", "name", "alias", "points", "limits"})
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetAlignment(tablewriter.ALIGN_CENTER)
 	table.SetCenterSeparator("|")
@@ -43,6 +43,7 @@ func SzkopulList() (err error) {
 			prob.Name,
 			prob.Alias,
 			prob.ParsePoint(),
+			parsedLimits((&szkopul_client.Info{RootPath: info.RootPath, ContestID: prob.Contest, StageID: prob.Stage, ProblemAlias: prob.Alias}).Path()),
 		})
 	}
 	table.Render()
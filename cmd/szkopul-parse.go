@@ -9,6 +9,7 @@ import (
 	"github.com/fatih/color"
 
 	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/metadata"
 	"github.com/Arapak/sio-tool/szkopul_client"
 )
 
@@ -41,14 +42,29 @@ func SzkopulParse() (err error) {
 	defer db.Close()
 
 	work := func() error {
-		_, paths, err := cln.Parse(info, db)
+		parsed, paths, err := cln.Parse(info, db)
 		if err != nil {
 			return err
 		}
+		for _, problem := range parsed {
+			m := &metadata.Problem{
+				Name:                   problem.Name,
+				Link:                   problem.Link,
+				Szkopul:                &problem.Info,
+				TimeLimitInSeconds:     problem.Limits.TimeLimitInSeconds,
+				MemoryLimitInMegabytes: problem.Limits.MemoryLimitInMegabytes,
+				IO:                     metadata.IOMode(problem.StandardIO),
+			}
+			if err := m.Update(problem.Path); err != nil {
+				color.Red(err.Error())
+			}
+		}
 		if cfg.GenAfterParse {
 			for _, path := range paths {
 				err = GenFiles(source, path, ext)
-				color.Red(err.Error())
+				if err != nil {
+					color.Red(err.Error())
+				}
 			}
 		}
 		return nil
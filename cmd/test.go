@@ -3,16 +3,9 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
 
 	"github.com/Arapak/sio-tool/config"
 	"github.com/Arapak/sio-tool/judge"
-	"github.com/Arapak/sio-tool/util"
-
-	"github.com/fatih/color"
 )
 
 func Test() (err error) {
@@ -21,16 +14,15 @@ func Test() (err error) {
 		return errors.New("you have to add at least one code template by `st config`")
 	}
 
-	filename, index, err := getOneCode(Args.File, cfg.Template, map[string]struct{}{})
+	p, err := findProgram(Args.File, "")
 	if err != nil {
 		return
 	}
-	template := cfg.Template[index]
-	path, full := filepath.Split(filename)
-	ext := filepath.Ext(filename)
-	file := full[:len(full)-len(ext)]
-	rand := util.RandString(8)
-	task := judge.ExtractTaskName(file)
+	task := judge.ExtractTaskName(p.file)
+	p.task = task
+	if err = p.useProfile(Args.Profile); err != nil {
+		return
+	}
 
 	samples := getSampleByName(task)
 	samplesWithName := true
@@ -42,57 +34,47 @@ func Test() (err error) {
 		}
 	}
 
-	filter := func(cmd string) string {
-		cmd = strings.ReplaceAll(cmd, "$%rand%$", rand)
-		cmd = strings.ReplaceAll(cmd, "$%path%$", path)
-		cmd = strings.ReplaceAll(cmd, "$%full%$", full)
-		cmd = strings.ReplaceAll(cmd, "$%file%$", file)
-		return cmd
+	if err = checkReportFormat(); err != nil {
+		return
 	}
-
-	run := func(script string) error {
-		if s := filter(script); len(s) > 0 {
-			fmt.Println(s)
-			cmds := util.SplitCmd(s)
-			cmd := exec.Command(cmds[0], cmds[1:]...)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-			return cmd.Run()
-		}
-		return nil
+	problem, err := loadProblemDefaults()
+	if err != nil {
+		return
 	}
-
-	if err = run(template.BeforeScript); err != nil {
+	if err = p.compile(); err != nil {
 		return
 	}
 
-	var oiejqOptions *judge.OiejqOptions
-	if Args.Oiejq {
-		err = judge.InstallSio2Jail()
-		if err != nil {
-			return
-		}
-		oiejqOptions = &judge.OiejqOptions{MemorylimitInMegaBytes: Args.MemoryLimit, TimeLimitInSeconds: Args.TimeLimit}
+	options, programs, err := judgeOptions(task)
+	if err != nil {
+		return
 	}
+	if err = p.applyProfile(options); err != nil {
+		return
+	}
+	options.FileIO = problemFileIO(problem)
 
-	if s := filter(template.Script); len(s) > 0 {
+	var verdicts []judge.Verdict
+	if s := p.command(); len(s) > 0 {
 		for _, i := range samples {
-			var verdict judge.Verdict
-
+			input, output := fmt.Sprintf("in%v.txt", i), fmt.Sprintf("out%v.txt", i)
 			if samplesWithName {
-				verdict = judge.Judge(fmt.Sprintf("%s%v.in", task, i), fmt.Sprintf("%s%v.out", task, i), i, s, oiejqOptions)
-			} else {
-				verdict = judge.Judge(fmt.Sprintf("in%v.txt", i), fmt.Sprintf("out%v.txt", i), i, s, oiejqOptions)
+				input, output = fmt.Sprintf("%s%v.in", task, i), fmt.Sprintf("%s%v.out", task, i)
 			}
+			verdict := judge.Judge(input, output, i, s, options)
 
-			if verdict.Err != nil {
-				color.Red(verdict.Err.Error())
-			} else {
-				fmt.Print(verdict.Message)
-			}
+			printVerdictWithStderr(verdict, i, input)
+			verdicts = append(verdicts, verdict)
 		}
 	} else {
-		return errors.New("invalid script command, please check config file")
+		return errors.New(ErrorInvalidScript)
+	}
+	recordRun(testRun, p, samples, verdicts)
+	if err = writeReport(newReport(task, samples, verdicts)); err != nil {
+		return
+	}
+	if err = cleanPrograms(programs); err != nil {
+		return
 	}
-	return run(template.AfterScript)
+	return p.clean()
 }
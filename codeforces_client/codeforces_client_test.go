@@ -43,3 +43,32 @@ func TestHighlightFindSample(t *testing.T) {
 		t.Errorf("Expect %s, but found %s.", expectOutput, realOutput)
 	}
 }
+
+func TestFindLimits(t *testing.T) {
+	body := "<div class=\"header\"><div class=\"title\">A. Watermelon</div><div class=\"time-limit\"><div class=\"property-title\">time limit per test</div>1.5 seconds</div><div class=\"memory-limit\"><div class=\"property-title\">memory limit per test</div>256 megabytes</div></div>"
+	limits, _ := findLimits([]byte(body))
+	if limits.TimeLimitInSeconds != 1.5 {
+		t.Errorf("Expect %v, but found %v.", 1.5, limits.TimeLimitInSeconds)
+	}
+	if limits.MemoryLimitInMegabytes != 256 {
+		t.Errorf("Expect %v, but found %v.", 256, limits.MemoryLimitInMegabytes)
+	}
+}
+
+func TestFindIOFiles(t *testing.T) {
+	tests := []struct {
+		body   string
+		input  string
+		output string
+	}{
+		{"<div class=\"input-file\"><div class=\"property-title\">input</div>standard input</div><div class=\"output-file\"><div class=\"property-title\">output</div>standard output</div>", "", ""},
+		{"<div class=\"input-file\"><div class=\"property-title\">input</div>input.txt</div><div class=\"output-file\"><div class=\"property-title\">output</div>output.txt</div>", "input.txt", "output.txt"},
+		{"<div class=\"input-file\"><div class=\"property-title\">input</div>cities.in</div><div class=\"output-file\"><div class=\"property-title\">output</div>standard output</div>", "cities.in", ""},
+	}
+	for _, test := range tests {
+		input, output, err := findIOFiles([]byte(test.body))
+		if err != nil || input != test.input || output != test.output {
+			t.Errorf("Expect %q and %q, but found %q and %q (%v).", test.input, test.output, input, output, err)
+		}
+	}
+}
@@ -20,7 +20,7 @@ type Info struct {
 	GroupID      string `json:"group_id"`
 	ProblemID    string `json:"problem_id"`
 	SubmissionID string `json:"submission_id"`
-	RootPath     string
+	RootPath     string `json:"-"`
 }
 
 const ErrorNeedProblemID = "you have to specify the Problem ID"
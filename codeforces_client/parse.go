@@ -1,16 +1,18 @@
 package codeforces_client
 
 import (
-	"bytes"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/Arapak/sio-tool/database_client"
+	"github.com/Arapak/sio-tool/judge"
 	"github.com/Arapak/sio-tool/util"
 
 	"github.com/PuerkitoBio/goquery"
@@ -47,13 +49,58 @@ func findSample(body []byte) (input [][]byte, output [][]byte, err error) {
 	return
 }
 
+var timeLimitReg = regexp.MustCompile(`(\d+(?:\.\d+)?) (ms|seconds?)`)
+var memoryLimitReg = regexp.MustCompile(`(\d+(?:\.\d+)?) (kilobytes|megabytes|gigabytes)`)
+
+// findLimits reads the limits from the header of the statement, e.g. "time limit per test2 seconds"
+func findLimits(body []byte) (limits judge.Limits, err error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+	if err != nil {
+		return
+	}
+	if match := timeLimitReg.FindStringSubmatch(doc.Find(".time-limit").First().Text()); match != nil {
+		limits.TimeLimitInSeconds, _ = strconv.ParseFloat(match[1], 64)
+		if match[2] == "ms" {
+			limits.TimeLimitInSeconds /= 1000
+		}
+	}
+	if match := memoryLimitReg.FindStringSubmatch(doc.Find(".memory-limit").First().Text()); match != nil {
+		limits.MemoryLimitInMegabytes, _ = strconv.ParseFloat(match[1], 64)
+		switch match[2] {
+		case "kilobytes":
+			limits.MemoryLimitInMegabytes /= 1024
+		case "gigabytes":
+			limits.MemoryLimitInMegabytes *= 1024
+		}
+	}
+	return
+}
+
 func findName(body []byte) (name string, err error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
 	name = doc.Find(".title").First().Text()
 	return
 }
 
-func (c *CodeforcesClient) ParseProblem(URL, path string, mu *sync.Mutex) (name string, samples int, standardIO bool, perf util.Performance, err error) {
+// findIOFiles returns the names of the input and output files, empty for the standard input and output
+func findIOFiles(body []byte) (inputFile, outputFile string, err error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+	if err != nil {
+		return
+	}
+	file := func(selector string) string {
+		property := doc.Find(selector).First()
+		property.Find(".property-title").Remove()
+		name := strings.TrimSpace(property.Text())
+		if name == "" || strings.HasPrefix(name, "standard ") {
+			return ""
+		}
+		return name
+	}
+	return file(".input-file"), file(".output-file"), nil
+}
+
+func (c *CodeforcesClient) ParseProblem(URL, path string, mu *sync.Mutex) (name string, samples int, inputFile, outputFile string, limits judge.Limits, perf util.Performance, err error) {
 	perf.StartFetching()
 
 	body, err := util.GetBody(c.client, URL)
@@ -74,14 +121,19 @@ func (c *CodeforcesClient) ParseProblem(URL, path string, mu *sync.Mutex) (name
 		return
 	}
 
+	limits, err = findLimits(body)
+	if err != nil {
+		return
+	}
+
 	input, output, err := findSample(body)
 	if err != nil {
 		return
 	}
 
-	standardIO = true
-	if !bytes.Contains(body, []byte(`<div class="input-file"><div class="property-title">input</div>standard input</div><div class="output-file"><div class="property-title">output</div>standard output</div>`)) {
-		standardIO = false
+	inputFile, outputFile, err = findIOFiles(body)
+	if err != nil {
+		return
 	}
 
 	perf.StopParsing()
@@ -110,10 +162,23 @@ func (c *CodeforcesClient) ParseProblem(URL, path string, mu *sync.Mutex) (name
 			}
 		}
 	}
-	return name, len(input), standardIO, perf, nil
+	return name, len(input), inputFile, outputFile, limits, perf, nil
+}
+
+// ParsedProblem describes a successfully parsed problem
+type ParsedProblem struct {
+	Info       Info
+	Name       string
+	Link       string
+	Path       string
+	StandardIO bool
+	// the files used instead of the standard input and output, empty if they aren't
+	InputFile  string
+	OutputFile string
+	Limits     judge.Limits
 }
 
-func (c *CodeforcesClient) Parse(info Info, db *sql.DB) (problems []string, paths []string, err error) {
+func (c *CodeforcesClient) Parse(info Info, db *sql.DB) (parsed []ParsedProblem, paths []string, err error) {
 	color.Cyan("Parse " + info.Hint())
 
 	start := time.Now()
@@ -125,6 +190,7 @@ func (c *CodeforcesClient) Parse(info Info, db *sql.DB) (problems []string, path
 		return
 	}
 	info.ProblemID = ""
+	var problems []string
 	if problemID == "" {
 		statics, perf, err := c.Statis(info)
 		if err != nil {
@@ -161,7 +227,7 @@ func (c *CodeforcesClient) Parse(info Info, db *sql.DB) (problems []string, path
 			}
 			URL := fmt.Sprintf(urlFormatter, problemID)
 
-			name, samples, standardIO, perf, err := c.ParseProblem(URL, path, &mu)
+			name, samples, inputFile, outputFile, limits, perf, err := c.ParseProblem(URL, path, &mu)
 			if err != nil {
 				return
 			}
@@ -172,6 +238,7 @@ func (c *CodeforcesClient) Parse(info Info, db *sql.DB) (problems []string, path
 
 			name = strings.TrimPrefix(name, fmt.Sprintf("%v. ", strings.ToUpper(problemID)))
 
+			standardIO := inputFile == "" && outputFile == ""
 			warns := ""
 			if !standardIO {
 				warns = color.YellowString("Non standard input output format.")
@@ -181,7 +248,7 @@ func (c *CodeforcesClient) Parse(info Info, db *sql.DB) (problems []string, path
 			if err != nil {
 				color.Red("Failed %v. Error: %v", problemID, err.Error())
 			} else {
-				_, _ = ansi.Printf("%v %v\n", color.GreenString("Parsed %v. %v with %v samples.", problemID, name, samples), warns)
+				_, _ = ansi.Printf("%v %v\n", color.GreenString("Parsed %v. %v with %v samples (%v).", problemID, name, samples, limits), warns)
 				task := database_client.Task{
 					Name:      name,
 					Source:    "cf",
@@ -194,6 +261,9 @@ func (c *CodeforcesClient) Parse(info Info, db *sql.DB) (problems []string, path
 				if err != nil {
 					color.Red(err.Error())
 				}
+				problemInfo := info
+				problemInfo.ProblemID = problemID
+				parsed = append(parsed, ParsedProblem{Info: problemInfo, Name: name, Link: URL, Path: path, StandardIO: standardIO, InputFile: inputFile, OutputFile: outputFile, Limits: limits})
 			}
 			mu.Unlock()
 		}(problemID, paths[i])
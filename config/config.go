@@ -16,13 +16,48 @@ import (
 )
 
 type CodeTemplate struct {
-	Alias        string   `json:"alias"`
-	Lang         string   `json:"lang"`
-	Path         string   `json:"path"`
-	Suffix       []string `json:"suffix"`
-	BeforeScript string   `json:"before_script"`
-	Script       string   `json:"script"`
-	AfterScript  string   `json:"after_script"`
+	Alias        string                  `json:"alias"`
+	Lang         string                  `json:"lang"`
+	Path         string                  `json:"path"`
+	Suffix       []string                `json:"suffix"`
+	BeforeScript string                  `json:"before_script"`
+	Script       string                  `json:"script"`
+	AfterScript  string                  `json:"after_script"`
+	Profiles     map[string]BuildProfile `json:"profiles,omitempty"`
+}
+
+// BuildProfile replaces the scripts of the template, the empty ones are taken from the template
+type BuildProfile struct {
+	BeforeScript string `json:"before_script"`
+	Script       string `json:"script"`
+	AfterScript  string `json:"after_script"`
+	// programs built with sanitizers reserve a lot of virtual memory, so the memory limit can't be enforced on them
+	NoMemoryLimit bool `json:"no_memory_limit"`
+}
+
+// ReleaseProfile uses the scripts of the template itself, unless it is defined explicitly
+const ReleaseProfile = "release"
+const DebugProfile = "debug"
+
+// WithProfile returns the template with the scripts of the given profile
+func (template CodeTemplate) WithProfile(name string) (CodeTemplate, BuildProfile, error) {
+	profile, ok := template.Profiles[name]
+	if !ok {
+		if name == "" || name == ReleaseProfile {
+			return template, BuildProfile{}, nil
+		}
+		return template, profile, fmt.Errorf("template %v has no profile %v, you can add it by `st config`", template.Alias, name)
+	}
+	if profile.BeforeScript != "" {
+		template.BeforeScript = profile.BeforeScript
+	}
+	if profile.Script != "" {
+		template.Script = profile.Script
+	}
+	if profile.AfterScript != "" {
+		template.AfterScript = profile.AfterScript
+	}
+	return template, profile, nil
 }
 
 type Config struct {
@@ -39,9 +74,18 @@ type Config struct {
 	DefaultNaming  map[string]string `json:"default_naming"`
 	DbPath         string            `json:"db_path"`
 	PackagesPath   string            `json:"packages_path"`
+	Backend        string            `json:"backend"`
 	path           string
 }
 
+const (
+	BackendProcess = "process"
+	BackendOiejq   = "oiejq"
+	BackendCgroup  = "cgroup"
+)
+
+var Backends = []string{BackendProcess, BackendOiejq, BackendCgroup}
+
 var Instance *Config
 
 func Init(path string) {
@@ -82,6 +126,10 @@ func Init(path string) {
 		}
 	}
 
+	if c.Backend == "" {
+		c.Backend = BackendProcess
+	}
+
 	if c.DefaultNaming == nil {
 		c.DefaultNaming = map[string]string{}
 	}
@@ -94,6 +142,15 @@ func Init(path string) {
 	if _, ok := c.DefaultNaming["gen"]; !ok {
 		c.DefaultNaming["gen"] = "$%task%$-gen.cpp"
 	}
+	if _, ok := c.DefaultNaming["checker"]; !ok {
+		c.DefaultNaming["checker"] = "$%task%$-chk.cpp"
+	}
+	if _, ok := c.DefaultNaming["interactor"]; !ok {
+		c.DefaultNaming["interactor"] = "$%task%$-interactor.cpp"
+	}
+	if _, ok := c.DefaultNaming["validator"]; !ok {
+		c.DefaultNaming["validator"] = "$%task%$-val.cpp"
+	}
 	if _, ok := c.DefaultNaming["test_in"]; !ok {
 		c.DefaultNaming["test_in"] = "$%task%$GenTest$%test%$.in"
 	}
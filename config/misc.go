@@ -191,6 +191,15 @@ func (c *Config) SetDefaultNaming() (err error) {
 	if c.DefaultNaming["gen"], err = inputDontOverwriteEmpty(`Tests generator filename`, c.DefaultNaming["gen"], nil); err != nil {
 		return
 	}
+	if c.DefaultNaming["checker"], err = inputDontOverwriteEmpty(`Checker filename`, c.DefaultNaming["checker"], nil); err != nil {
+		return
+	}
+	if c.DefaultNaming["interactor"], err = inputDontOverwriteEmpty(`Interactor filename`, c.DefaultNaming["interactor"], nil); err != nil {
+		return
+	}
+	if c.DefaultNaming["validator"], err = inputDontOverwriteEmpty(`Validator filename`, c.DefaultNaming["validator"], nil); err != nil {
+		return
+	}
 	fmt.Printf(`Here you can also insert $%%test%%$ placeholder in your filename, which will indicate the test number.`)
 	if c.DefaultNaming["test_in"], err = inputDontOverwriteEmpty(`Generated test filename`, c.DefaultNaming["test_in"], nil); err != nil {
 		return
@@ -223,3 +232,18 @@ func (c *Config) SetDbPath() (err error) {
 	color.Green("New database path is %v", dbPath)
 	return c.save()
 }
+
+func (c *Config) SetBackend() (err error) {
+	color.Cyan(`process: runs tests directly, limits are enforced with rlimits`)
+	color.Cyan(`oiejq: runs tests in sio2jail, needs kernel.perf_event_paranoid=-1`)
+	color.Cyan(`cgroup: runs every test in a separate cgroup v2, needs delegated memory and pids controllers`)
+	prompt := &survey.Select{
+		Message: `Select the default judging backend`,
+		Options: Backends,
+		Default: c.Backend,
+	}
+	if err = survey.AskOne(prompt, &c.Backend); err != nil {
+		return
+	}
+	return c.save()
+}
@@ -29,6 +29,8 @@ var oiTemplatePath = "~/.st/template.cpp"
 var oiTemplateCompilation = "g++ -std=c++20 -Wpedantic -O3 -static -o $%path%$$%file%$.e $%path%$$%full%$"
 var oiTemplateRun = "./$%path%$$%file%$.e"
 
+var oiTemplateDebugCompilation = "g++ -std=c++20 -Wall -Wextra -O1 -g -fsanitize=address,undefined -fno-omit-frame-pointer -D_GLIBCXX_DEBUG -DLOCAL -o $%path%$$%file%$.e $%path%$$%full%$"
+
 func (c *Config) AddOiTemplate() (err error) {
 	oiTemplatePath, err = homedir.Expand(oiTemplatePath)
 	if err != nil {
@@ -41,6 +43,9 @@ func (c *Config) AddOiTemplate() (err error) {
 	c.Template = append(c.Template, CodeTemplate{
 		"oi-cpp", "54", oiTemplatePath, []string{"cpp", "cxx", "cc"},
 		oiTemplateCompilation, oiTemplateRun, "",
+		map[string]BuildProfile{
+			DebugProfile: {BeforeScript: oiTemplateDebugCompilation, NoMemoryLimit: true},
+		},
 	})
 	return c.save()
 }
@@ -130,7 +130,7 @@ func (c *Config) AddTemplate() (err error) {
 
 	c.Template = append(c.Template, CodeTemplate{
 		alias, langs[langID].K, path, suffix,
-		beforeScript, script, afterScript,
+		beforeScript, script, afterScript, nil,
 	})
 	makeItDefault := true
 	prompt := &survey.Confirm{Message: `Make it default?`, Default: true}
@@ -198,6 +198,44 @@ func (c *Config) SetDefaultTemplate() (err error) {
 	return c.save()
 }
 
+func (c *Config) AddProfile() (err error) {
+	if len(c.Template) == 0 {
+		color.Red("There is no template. Please add one")
+		return nil
+	}
+
+	templates := make([]string, len(c.Template))
+	for i, template := range c.Template {
+		templates[i] = fmt.Sprintf(`"%v" "%v"`, template.Alias, template.Path)
+	}
+	idx := 0
+	if err = survey.AskOne(&survey.Select{Message: "Add a build profile to a template", Options: templates}, &idx); err != nil {
+		return
+	}
+
+	note := `A build profile (e.g. "debug" or "asan") replaces the scripts of the template,
+  select it with "st test --profile debug". The scripts left empty are taken from the template.
+  The "debug" profile is also used to re-run failing tests with "--debug_failing".`
+	_, _ = ansi.Println(note)
+
+	name := ""
+	util.GetValue(`Profile's name (e.g. "debug"):`, &name, true)
+	profile := BuildProfile{}
+	util.GetValue(`Before script (e.g. "g++ $%full%$ -o $%file%$.e -g -fsanitize=address,undefined -D_GLIBCXX_DEBUG"), empty is ok:`, &profile.BeforeScript, false)
+	util.GetValue(`Script, empty is ok:`, &profile.Script, false)
+	util.GetValue(`After script, empty is ok:`, &profile.AfterScript, false)
+	prompt := &survey.Confirm{Message: `Disable the memory limit (needed for AddressSanitizer)?`, Default: false}
+	if err = survey.AskOne(prompt, &profile.NoMemoryLimit); err != nil {
+		return
+	}
+
+	if c.Template[idx].Profiles == nil {
+		c.Template[idx].Profiles = make(map[string]BuildProfile)
+	}
+	c.Template[idx].Profiles[name] = profile
+	return c.save()
+}
+
 func (c *Config) TemplateByAlias(alias string) []CodeTemplate {
 	var ret []CodeTemplate
 	for _, template := range c.Template {
@@ -0,0 +1,159 @@
+package database_client
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Run is a local run of a solution on the samples (test) or on a package (package_test)
+type Run struct {
+	ID         int
+	Problem    string
+	Kind       string
+	File       string
+	SourceHash string
+	Time       time.Time
+	Results    []TestResult
+}
+
+type TestResult struct {
+	Test              string
+	Status            string
+	TimeInSeconds     float64
+	MemoryInMegabytes float64
+	Points            float64
+}
+
+func createRunTablesIfNotExist(db *sql.DB) error {
+	sqlStatement := `
+		CREATE TABLE IF NOT EXISTS runs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			problem TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			file TEXT NOT NULL,
+			source_hash TEXT NOT NULL,
+			time INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS runs_problem ON runs(problem);
+		CREATE TABLE IF NOT EXISTS run_tests (
+			run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+			test TEXT NOT NULL,
+			status TEXT NOT NULL,
+			time REAL NOT NULL,
+			memory REAL NOT NULL,
+			points REAL NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS run_tests_run_id ON run_tests(run_id);
+  `
+	_, err := db.Exec(sqlStatement)
+	if err != nil {
+		return fmt.Errorf("failed to create table: %v", err)
+	}
+	return nil
+}
+
+func AddRun(db *sql.DB, r Run) (id int, err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add run to database: %v", err)
+	}
+	result, err := tx.Exec(`
+        INSERT INTO runs(problem, kind, file, source_hash, time)
+        VALUES (?, ?, ?, ?, ?)
+    `, r.Problem, r.Kind, r.File, r.SourceHash, r.Time.Unix())
+	if err != nil {
+		_ = tx.Rollback()
+		if strings.Contains(err.Error(), `no such table: runs`) {
+			if err = createRunTablesIfNotExist(db); err != nil {
+				return 0, err
+			}
+			return AddRun(db, r)
+		}
+		return 0, fmt.Errorf("failed to add run to database: %v", err)
+	}
+	runID, err := result.LastInsertId()
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("failed to add run to database: %v", err)
+	}
+	for _, test := range r.Results {
+		_, err = tx.Exec(`
+            INSERT INTO run_tests(run_id, test, status, time, memory, points)
+            VALUES (?, ?, ?, ?, ?, ?)
+        `, runID, test.Test, test.Status, test.TimeInSeconds, test.MemoryInMegabytes, test.Points)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("failed to add run to database: %v", err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to add run to database: %v", err)
+	}
+	return int(runID), nil
+}
+
+// FindRuns returns the runs of the problem from the oldest, together with their results
+func FindRuns(db *sql.DB, problem string) ([]Run, error) {
+	var runs []Run
+	rows, err := db.Query(`
+	    SELECT id, problem, kind, file, source_hash, time
+	    FROM runs
+	    WHERE problem = ?
+	    ORDER BY id
+	`, problem)
+	if err != nil {
+		if strings.Contains(err.Error(), `no such table: runs`) {
+			if err = createRunTablesIfNotExist(db); err != nil {
+				return nil, err
+			}
+			return FindRuns(db, problem)
+		}
+		return nil, fmt.Errorf("failed to find runs in database: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var run Run
+		var unixTime int64
+		if err := rows.Scan(&run.ID, &run.Problem, &run.Kind, &run.File, &run.SourceHash, &unixTime); err != nil {
+			return nil, fmt.Errorf("failed to scan run row: %v", err)
+		}
+		run.Time = time.Unix(unixTime, 0)
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read run rows: %v", err)
+	}
+	for i := range runs {
+		if runs[i].Results, err = findTestResults(db, runs[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return runs, nil
+}
+
+func findTestResults(db *sql.DB, runID int) ([]TestResult, error) {
+	var results []TestResult
+	rows, err := db.Query(`
+	    SELECT test, status, time, memory, points
+	    FROM run_tests
+	    WHERE run_id = ?
+	    ORDER BY rowid
+	`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the results of run %v in database: %v", runID, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var result TestResult
+		if err := rows.Scan(&result.Test, &result.Status, &result.TimeInSeconds, &result.MemoryInMegabytes, &result.Points); err != nil {
+			return nil, fmt.Errorf("failed to scan test result row: %v", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read test result rows: %v", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,51 @@
+package judge
+
+import (
+	"errors"
+	"io"
+)
+
+// CgroupOptions configure running programs in a transient cgroup v2,
+// an alternative to sio2jail which doesn't need perf events
+type CgroupOptions struct {
+	Limits                 Limits
+	PidsLimit              int
+	OutputLimitInMegabytes float64
+	// Isolate runs the program in new mount, network and ipc namespaces
+	Isolate bool
+}
+
+const ErrorCgroupUnavailable = "cgroup v2 with delegated memory and pids controllers is not available"
+const ErrorOutputLimitExceeded = "output limit exceeded"
+
+const defaultPidsLimit = 64
+const defaultOutputLimitInMegabytes = 256
+
+func NewCgroupOptions(limits Limits, isolate bool) *CgroupOptions {
+	return &CgroupOptions{
+		Limits:                 limits,
+		PidsLimit:              defaultPidsLimit,
+		OutputLimitInMegabytes: defaultOutputLimitInMegabytes,
+		Isolate:                isolate,
+	}
+}
+
+// limitedWriter fails after writing more than limit bytes, which closes the program's output pipe
+type limitedWriter struct {
+	w         io.Writer
+	remaining int64
+	exceeded  bool
+}
+
+func newLimitedWriter(w io.Writer, limitInMegabytes float64) *limitedWriter {
+	return &limitedWriter{w: w, remaining: int64(limitInMegabytes * 1024 * 1024)}
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining {
+		l.exceeded = true
+		return 0, errors.New(ErrorOutputLimitExceeded)
+	}
+	l.remaining -= int64(len(p))
+	return l.w.Write(p)
+}
@@ -0,0 +1,348 @@
+package judge
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Arapak/sio-tool/util"
+)
+
+var cgroupControllers = []string{"memory", "pids"}
+
+// st moves itself here when it's the only process of its cgroup, because a cgroup with enabled
+// controllers can't contain processes
+const cgroupSupervisor = "st-supervisor"
+
+var cgroupOnce sync.Once
+var cgroupBase string
+var cgroupErr error
+
+func findCgroupMount() (string, error) {
+	file, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if field == "-" && i+1 < len(fields) && fields[i+1] == "cgroup2" && len(fields) > 4 {
+				return fields[4], nil
+			}
+		}
+	}
+	return "", errors.New("cgroup2 filesystem is not mounted")
+}
+
+func findOwnCgroup() (string, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			return strings.TrimPrefix(line, "0::"), nil
+		}
+	}
+	return "", errors.New("st is not in a cgroup v2")
+}
+
+// enableControllers enables the controllers which aren't enabled yet in path, they are never disabled again
+// because other cgroups may depend on them
+func enableControllers(path string) error {
+	subtreeControl, err := os.ReadFile(filepath.Join(path, "cgroup.subtree_control"))
+	if err != nil {
+		return err
+	}
+	enabled := map[string]bool{}
+	for _, controller := range strings.Fields(string(subtreeControl)) {
+		enabled[controller] = true
+	}
+	var controllers []string
+	for _, controller := range cgroupControllers {
+		if !enabled[controller] {
+			controllers = append(controllers, "+"+controller)
+		}
+	}
+	if len(controllers) == 0 {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(path, "cgroup.subtree_control"), []byte(strings.Join(controllers, " ")), 0644)
+}
+
+// onlyProcess tells if st is the only process in the cgroup
+func onlyProcess(path string) (bool, error) {
+	procs, err := os.ReadFile(filepath.Join(path, "cgroup.procs"))
+	if err != nil {
+		return false, err
+	}
+	pids := strings.Fields(string(procs))
+	return len(pids) == 1 && pids[0] == strconv.Itoa(os.Getpid()), nil
+}
+
+// delegateControllers makes the controllers available to the children of the own cgroup of st
+func delegateControllers(own string) (err error) {
+	if err = enableControllers(own); err == nil {
+		return
+	}
+	// st only rearranges a cgroup delegated to it alone, e.g. the scope made by systemd-run
+	if only, err := onlyProcess(own); err != nil || !only {
+		return fmt.Errorf("%v: st isn't the only process in %v, run it in its own cgroup with `systemd-run --user --scope -p Delegate=yes st ...`", ErrorCgroupUnavailable, own)
+	}
+	// st stays in the supervisor until it exits, it can't come back to a cgroup with enabled controllers
+	supervisor := filepath.Join(own, cgroupSupervisor)
+	if err = os.Mkdir(supervisor, 0755); err != nil && !os.IsExist(err) {
+		return fmt.Errorf("%v: %v", ErrorCgroupUnavailable, err)
+	}
+	if err = os.WriteFile(filepath.Join(supervisor, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		_ = os.Remove(supervisor)
+		return fmt.Errorf("%v: %v", ErrorCgroupUnavailable, err)
+	}
+	if err = enableControllers(own); err != nil {
+		return fmt.Errorf("%v: cannot enable controllers in %v (%v), try running st with `systemd-run --user --scope -p Delegate=yes`", ErrorCgroupUnavailable, own, err)
+	}
+	return
+}
+
+// setupCgroup creates the cgroup of st in which the cgroups of the programs are made
+func setupCgroup() (base string, err error) {
+	mount, err := findCgroupMount()
+	if err != nil {
+		return
+	}
+	ownPath, err := findOwnCgroup()
+	if err != nil {
+		return
+	}
+	own := filepath.Join(mount, ownPath)
+	available, err := os.ReadFile(filepath.Join(own, "cgroup.controllers"))
+	if err != nil {
+		return
+	}
+	availableControllers := map[string]bool{}
+	for _, controller := range strings.Fields(string(available)) {
+		availableControllers[controller] = true
+	}
+	for _, controller := range cgroupControllers {
+		if !availableControllers[controller] {
+			return "", fmt.Errorf("%v: %v controller is not available in %v", ErrorCgroupUnavailable, controller, own)
+		}
+	}
+	if err = delegateControllers(own); err != nil {
+		return
+	}
+	base = filepath.Join(own, fmt.Sprintf("st-%v", os.Getpid()))
+	if err = os.Mkdir(base, 0755); err != nil {
+		return "", fmt.Errorf("%v: %v", ErrorCgroupUnavailable, err)
+	}
+	if err = enableControllers(base); err != nil {
+		_ = os.Remove(base)
+		return "", fmt.Errorf("%v: cannot enable controllers in %v (%v)", ErrorCgroupUnavailable, base, err)
+	}
+	return
+}
+
+// CheckCgroup prepares the cgroup in which the tests are run
+func CheckCgroup() error {
+	cgroupOnce.Do(func() {
+		cgroupBase, cgroupErr = setupCgroup()
+	})
+	return cgroupErr
+}
+
+// ReleaseCgroup removes the cgroup created by CheckCgroup
+func ReleaseCgroup() {
+	if cgroupBase != "" {
+		_ = os.Remove(cgroupBase)
+		cgroupBase = ""
+	}
+}
+
+type cgroup struct {
+	path string
+}
+
+func newCgroup(options *CgroupOptions) (c *cgroup, err error) {
+	c = &cgroup{filepath.Join(cgroupBase, "st-"+util.RandString(8))}
+	if err = os.Mkdir(c.path, 0755); err != nil {
+		return nil, err
+	}
+	memoryMax := strconv.FormatUint(options.Limits.memoryLimitInBytes(), 10)
+	if !options.Limits.memoryLimited() {
+		memoryMax = "max"
+	}
+	if err = c.write("memory.max", memoryMax); err != nil {
+		c.remove()
+		return nil, err
+	}
+	// without swap the memory limit can't be bypassed, but the file doesn't exist if swap accounting is off
+	_ = c.write("memory.swap.max", "0")
+	if err = c.write("pids.max", strconv.Itoa(options.PidsLimit)); err != nil {
+		c.remove()
+		return nil, err
+	}
+	return
+}
+
+func (c *cgroup) write(file, value string) error {
+	return os.WriteFile(filepath.Join(c.path, file), []byte(value), 0644)
+}
+
+// readKey reads a value from a flat keyed file like cpu.stat or memory.events
+func (c *cgroup) readKey(file, key string) (value uint64, err error) {
+	data, err := os.ReadFile(filepath.Join(c.path, file))
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("%v not found in %v", key, file)
+}
+
+// attach moves the program stopped at its exec into the cgroup, so it doesn't run a single instruction outside of it
+func (c *cgroup) attach(pid int) (err error) {
+	var status syscall.WaitStatus
+	if _, err = syscall.Wait4(pid, &status, 0, nil); err != nil {
+		return
+	}
+	if !status.Stopped() {
+		return errors.New("the program didn't stop after exec")
+	}
+	if err = c.write("cgroup.procs", strconv.Itoa(pid)); err != nil {
+		return
+	}
+	return syscall.PtraceDetach(pid)
+}
+
+func (c *cgroup) kill(cmd *exec.Cmd) {
+	if c.write("cgroup.kill", "1") != nil {
+		_ = killProcessGroup(cmd)
+	}
+}
+
+func (c *cgroup) remove() {
+	// the killed processes may still be exiting
+	for i := 0; i < 10; i++ {
+		err := os.Remove(c.path)
+		if err == nil || os.IsNotExist(err) {
+			return
+		}
+		_ = c.write("cgroup.kill", "1")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func isolate(attr *syscall.SysProcAttr) {
+	attr.Cloneflags = syscall.CLONE_NEWNS | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC
+	if os.Geteuid() != 0 {
+		attr.Cloneflags |= syscall.CLONE_NEWUSER
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Geteuid(), HostID: os.Geteuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getegid(), HostID: os.Getegid(), Size: 1}}
+	}
+}
+
+func runProcessInCgroup(command, dir string, input io.Reader, output io.Writer, options *CgroupOptions) (ProcessInfo, error) {
+	if err := CheckCgroup(); err != nil {
+		return ProcessInfo{INT, 0, 0, []byte{}, []byte{}}, err
+	}
+	c, err := newCgroup(options)
+	if err != nil {
+		return ProcessInfo{INT, 0, 0, []byte{}, []byte{}}, err
+	}
+	defer c.remove()
+
+	var o bytes.Buffer
+	if output == nil {
+		output = io.Writer(&o)
+	}
+	limitedOutput := newLimitedWriter(output, options.OutputLimitInMegabytes)
+	var e bytes.Buffer
+
+	cmds := util.SplitCmd(command)
+	cmd := exec.Command(cmds[0], cmds[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = input
+	cmd.Stdout = limitedOutput
+	cmd.Stderr = &e
+	setProcessGroup(cmd)
+	cmd.SysProcAttr.Ptrace = true
+	if options.Isolate {
+		isolate(cmd.SysProcAttr)
+	}
+
+	// the tracee can only be detached by the thread which started it
+	runtime.LockOSThread()
+	if err = cmd.Start(); err != nil {
+		runtime.UnlockOSThread()
+		return ProcessInfo{RE, 0, 0, []byte{}, []byte{}}, err
+	}
+	err = c.attach(cmd.Process.Pid)
+	runtime.UnlockOSThread()
+	if err != nil {
+		_ = killProcessGroup(cmd)
+		_ = cmd.Wait()
+		return ProcessInfo{INT, 0, 0, []byte{}, []byte{}}, err
+	}
+
+	timer := time.NewTimer(options.Limits.wallTimeLimit())
+	defer timer.Stop()
+	ch := make(chan error, 1)
+	go func() {
+		ch <- cmd.Wait()
+	}()
+	timedOut := false
+	select {
+	case err = <-ch:
+	case <-timer.C:
+		timedOut = true
+		c.kill(cmd)
+		err = <-ch
+	}
+
+	timeInSeconds := (cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()).Seconds()
+	if usage, err := c.readKey("cpu.stat", "usage_usec"); err == nil {
+		timeInSeconds = float64(usage) / 1e6
+	}
+	memory, peakErr := os.ReadFile(filepath.Join(c.path, "memory.peak"))
+	var peak uint64
+	if peakErr == nil {
+		peak, peakErr = strconv.ParseUint(strings.TrimSpace(string(memory)), 10, 64)
+	}
+	if peakErr != nil {
+		// memory.peak is available since Linux 5.19
+		peak = memoryWatcher{}.peak(cmd.ProcessState)
+	}
+	memoryInMegabytes := float64(peak) / (1024.0 * 1024.0)
+	oomKills, _ := c.readKey("memory.events", "oom_kill")
+
+	if timedOut || timeInSeconds > options.Limits.TimeLimitInSeconds {
+		return ProcessInfo{TLE, timeInSeconds, memoryInMegabytes, []byte{}, e.Bytes()}, nil
+	}
+	if oomKills > 0 || (options.Limits.memoryLimited() && memoryInMegabytes > options.Limits.MemoryLimitInMegabytes) {
+		return ProcessInfo{MLE, timeInSeconds, memoryInMegabytes, []byte{}, e.Bytes()}, nil
+	}
+	if limitedOutput.exceeded {
+		return ProcessInfo{OLE, timeInSeconds, memoryInMegabytes, []byte{}, e.Bytes()}, nil
+	}
+	if err != nil {
+		return ProcessInfo{RE, timeInSeconds, memoryInMegabytes, []byte{}, e.Bytes()}, err
+	}
+	return ProcessInfo{OK, timeInSeconds, memoryInMegabytes, o.Bytes(), e.Bytes()}, nil
+}
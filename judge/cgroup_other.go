@@ -0,0 +1,18 @@
+//go:build !linux
+
+package judge
+
+import (
+	"errors"
+	"io"
+)
+
+func CheckCgroup() error {
+	return errors.New(ErrorCgroupUnavailable)
+}
+
+func ReleaseCgroup() {}
+
+func runProcessInCgroup(command, dir string, input io.Reader, output io.Writer, options *CgroupOptions) (ProcessInfo, error) {
+	return ProcessInfo{INT, 0, 0, []byte{}, []byte{}}, errors.New(ErrorCgroupUnavailable)
+}
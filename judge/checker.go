@@ -0,0 +1,149 @@
+package judge
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Checker is a program deciding whether an output is correct. It is invoked as
+// `checker in out ans` and can follow either the testlib convention (verdict in
+// the exit code, message on stderr) or the Sinol one ("OK"/"WRONG", a comment
+// and optional points printed on stdout).
+type Checker struct {
+	Command string
+}
+
+const (
+	testlibOK     = 0
+	testlibWA     = 1
+	testlibPE     = 2
+	testlibFail   = 3
+	testlibPoints = 7
+)
+
+const ErrorCheckerFailed = "checker failed"
+const ErrorCheckerTimeout = "the checker exceeded the time limit"
+
+// the checker is stopped when it runs longer than this, it isn't limited otherwise
+var checkerLimits = Limits{TimeLimitInSeconds: 10}
+
+type CheckerResult struct {
+	Status  VerdictStatus
+	Message string
+	Points  float64
+}
+
+func parseSinolChecker(output []byte) (result CheckerResult, ok bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	switch lines[0] {
+	case "OK":
+		result.Status = OK
+		result.Points = 100
+	case "WRONG":
+		result.Status = WA
+	default:
+		return
+	}
+	if len(lines) > 1 {
+		result.Message = lines[1]
+	}
+	if len(lines) > 2 && result.Status == OK {
+		if points, err := strconv.ParseFloat(lines[2], 64); err == nil {
+			result.Points = points
+		}
+	}
+	return result, true
+}
+
+func parseTestlibPoints(message string) float64 {
+	fields := strings.Fields(message)
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "points" {
+			if points, err := strconv.ParseFloat(fields[i+1], 64); err == nil {
+				return points
+			}
+		}
+	}
+	return 0
+}
+
+// parseTestlibResult reads the verdict of a testlib checker or interactor from its exit code
+func parseTestlibResult(stderr []byte, runErr error) (result CheckerResult, err error) {
+	result.Message = strings.TrimSpace(string(stderr))
+	exitCode := testlibOK
+	if runErr != nil {
+		var exitError *exec.ExitError
+		if !errors.As(runErr, &exitError) {
+			result.Status = INT
+			return result, runErr
+		}
+		exitCode = exitError.ExitCode()
+	}
+	switch exitCode {
+	case testlibOK:
+		result.Status = OK
+		result.Points = 100
+	case testlibWA, testlibPE:
+		result.Status = WA
+	case testlibPoints:
+		result.Status = OK
+		result.Points = parseTestlibPoints(result.Message)
+	case testlibFail:
+		err = fmt.Errorf("%v: %v", ErrorCheckerFailed, result.Message)
+	default:
+		err = fmt.Errorf("%v with exit code %v: %v", ErrorCheckerFailed, exitCode, result.Message)
+	}
+	if err != nil {
+		result.Status = INT
+	}
+	return
+}
+
+func (checker *Checker) Check(inPath, outPath, ansPath string) (result CheckerResult, err error) {
+	command := fmt.Sprintf(`%v "%v" "%v" "%v"`, checker.Command, inPath, outPath, ansPath)
+	processInfo, err := runProcess(command, bytes.NewReader([]byte{}), nil, nil, &checkerLimits)
+	if processInfo.Status == TLE {
+		return CheckerResult{Status: INT}, errors.New(ErrorCheckerTimeout)
+	}
+	if sinolResult, ok := parseSinolChecker(processInfo.Output); ok {
+		return sinolResult, nil
+	}
+	return parseTestlibResult(processInfo.Stderr, err)
+}
+
+func (checker *Checker) Verdict(testID, inPath, ansPath string, processInfo ProcessInfo) Verdict {
+	output, err := os.CreateTemp(os.TempDir(), "st-output-")
+	if err != nil {
+		return Verdict{Status: INT, Err: err}
+	}
+	defer os.Remove(output.Name())
+	_, err = output.Write(processInfo.Output)
+	output.Close()
+	if err != nil {
+		return Verdict{Status: INT, Err: err}
+	}
+
+	result, err := checker.Check(inPath, output.Name(), ansPath)
+	if err != nil {
+		return Verdict{Status: INT, TimeInSeconds: processInfo.TimeInSeconds, MemoryInMegabytes: processInfo.MemoryInMegabytes, Err: err}
+	}
+	diff := ""
+	if result.Status != OK {
+		answer, _ := os.ReadFile(ansPath)
+		diff = formatOutputAndAnswer(Plain(processInfo.Output), Plain(answer))
+	}
+	return newVerdict(testID, result.Status, result.Message, result.Points, diff, processInfo)
+}
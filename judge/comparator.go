@@ -0,0 +1,186 @@
+package judge
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	CompareLines          = "lines"
+	CompareExact          = "exact"
+	CompareTokens         = "tokens"
+	CompareTokensIgnore   = "tokens-ignore-case"
+	CompareFloat          = "float"
+	CompareUnorderedLines = "unordered-lines"
+)
+
+const defaultFloatEpsilon = 1e-6
+const comparatorSpecSeparator = ":"
+
+var Comparators = []string{CompareLines, CompareExact, CompareTokens, CompareTokensIgnore, CompareFloat, CompareUnorderedLines}
+
+// Comparator decides whether the output matches the answer without running a checker.
+// The default one (lines) compares the outputs after judge.Plain.
+type Comparator struct {
+	Mode    string
+	Epsilon float64
+}
+
+// ParseComparator parses a comparator given as "mode", or "float:epsilon" for the floating point one
+func ParseComparator(spec string) (comparator *Comparator, err error) {
+	mode, parameter, hasParameter := strings.Cut(spec, comparatorSpecSeparator)
+	comparator = &Comparator{Mode: mode, Epsilon: defaultFloatEpsilon}
+	switch mode {
+	case CompareLines, CompareExact, CompareTokens, CompareTokensIgnore, CompareUnorderedLines:
+		if hasParameter {
+			return nil, fmt.Errorf("comparator %v doesn't take parameters", mode)
+		}
+	case CompareFloat:
+		if hasParameter {
+			comparator.Epsilon, err = strconv.ParseFloat(parameter, 64)
+			if err != nil || comparator.Epsilon < 0 {
+				return nil, fmt.Errorf("invalid epsilon: %v", parameter)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown comparator: %v (available: %v)", mode, strings.Join(Comparators, ", "))
+	}
+	return
+}
+
+func (comparator *Comparator) String() string {
+	if comparator.Mode == CompareFloat {
+		return fmt.Sprintf("%v%v%v", CompareFloat, comparatorSpecSeparator, comparator.Epsilon)
+	}
+	return comparator.Mode
+}
+
+func equalFloats(output, answer string, epsilon float64) bool {
+	if output == answer {
+		return true
+	}
+	a, err := strconv.ParseFloat(answer, 64)
+	if err != nil {
+		return false
+	}
+	o, err := strconv.ParseFloat(output, 64)
+	if err != nil || math.IsNaN(o) {
+		return false
+	}
+	difference := math.Abs(o - a)
+	return difference <= epsilon || difference <= epsilon*math.Abs(a)
+}
+
+func equalTokens(output, answer []string, equal func(o, a string) bool) bool {
+	if len(output) != len(answer) {
+		return false
+	}
+	for i := range output {
+		if !equal(output[i], answer[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedLines(raw []byte) []string {
+	lines := strings.Split(strings.TrimSuffix(Plain(raw), "\n"), "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+func (comparator *Comparator) Compare(output, answer []byte) bool {
+	if comparator == nil {
+		return Plain(output) == Plain(answer)
+	}
+	switch comparator.Mode {
+	case CompareExact:
+		return bytes.Equal(output, answer)
+	case CompareTokens:
+		return equalTokens(strings.Fields(string(output)), strings.Fields(string(answer)), func(o, a string) bool {
+			return o == a
+		})
+	case CompareTokensIgnore:
+		return equalTokens(strings.Fields(string(output)), strings.Fields(string(answer)), strings.EqualFold)
+	case CompareFloat:
+		return equalTokens(strings.Fields(string(output)), strings.Fields(string(answer)), func(o, a string) bool {
+			return equalFloats(o, a, comparator.Epsilon)
+		})
+	case CompareUnorderedLines:
+		return equalTokens(sortedLines(output), sortedLines(answer), func(o, a string) bool {
+			return o == a
+		})
+	}
+	return Plain(output) == Plain(answer)
+}
+
+func (comparator *Comparator) mode() string {
+	if comparator == nil {
+		return CompareLines
+	}
+	return comparator.Mode
+}
+
+// lines returns the lines in the form in which they are compared, with their numbers in the raw output
+func (comparator *Comparator) lines(raw []byte) comparedLines {
+	switch comparator.mode() {
+	case CompareExact:
+		return numberedLines(splitLines(string(raw)))
+	case CompareUnorderedLines:
+		return sortedPlainLines(raw)
+	}
+	return plainLines(raw)
+}
+
+// difference finds the first difference, with the lines counted in the compared lines
+func (comparator *Comparator) difference(outputLines, answerLines comparedLines) *Difference {
+	switch comparator.mode() {
+	case CompareTokens:
+		return findTokenDifference(outputLines.text, answerLines.text, func(o, a string) bool {
+			return o == a
+		})
+	case CompareTokensIgnore:
+		return findTokenDifference(outputLines.text, answerLines.text, strings.EqualFold)
+	case CompareFloat:
+		return findTokenDifference(outputLines.text, answerLines.text, func(o, a string) bool {
+			return equalFloats(o, a, comparator.Epsilon)
+		})
+	}
+	difference := findDifference(outputLines.text, answerLines.text, func(o, a string) bool {
+		return o == a
+	})
+	if difference == nil && comparator.mode() == CompareExact {
+		// the outputs differ only in the trailing newline
+		difference = &Difference{Line: len(outputLines.text) + 1, AnswerLine: len(outputLines.text) + 1}
+	}
+	return difference
+}
+
+// Difference finds the first place where the output doesn't match the answer, nil if they match
+func (comparator *Comparator) Difference(output, answer []byte) *Difference {
+	outputLines, answerLines := comparator.lines(output), comparator.lines(answer)
+	difference := comparator.difference(outputLines, answerLines)
+	if difference == nil {
+		return nil
+	}
+	return difference.renumbered(outputLines, answerLines)
+}
+
+// Verdict compares the output of the program with the answer
+func (comparator *Comparator) Verdict(testID string, answer []byte, processInfo ProcessInfo, sideBySide bool) Verdict {
+	if comparator.Compare(processInfo.Output, answer) {
+		return newVerdict(testID, OK, "", 100, "", processInfo)
+	}
+	outputLines, answerLines := comparator.lines(processInfo.Output), comparator.lines(answer)
+	difference := comparator.difference(outputLines, answerLines)
+	if difference == nil {
+		return newVerdict(testID, WA, "", 0, formatOutputAndAnswer(string(processInfo.Output), string(answer)), processInfo)
+	}
+	verdict := newVerdict(testID, WA, "", 0, formatDiff(outputLines, answerLines, difference, sideBySide), processInfo)
+	verdict.Difference = difference.renumbered(outputLines, answerLines)
+	return verdict
+}
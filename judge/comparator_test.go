@@ -0,0 +1,64 @@
+package judge
+
+import "testing"
+
+func TestComparators(t *testing.T) {
+	tests := []struct {
+		spec   string
+		output string
+		answer string
+		expect bool
+	}{
+		{CompareLines, "1 2 \n\n3\n", "1 2\n3\n", true},
+		{CompareLines, "1  2\n3\n", "1 2\n3\n", false},
+		{CompareExact, "1 2\n3\n", "1 2\n3", false},
+		{CompareExact, "1 2\n3\n", "1 2\n3\n", true},
+		{CompareTokens, "1  2\n\n3", "1 2 3\n", true},
+		{CompareTokens, "1 2", "1 2 3\n", false},
+		{CompareTokensIgnore, "YES\nno", "yes NO", true},
+		{CompareFloat, "0.3333333", "0.333333333", true},
+		{CompareFloat, "0.334", "0.333333333", false},
+		{"float:1e-2", "1000001", "1000000", true},
+		{CompareFloat, "nan", "1", false},
+		{CompareFloat, "abc 1.0", "abc 1.0000000001", true},
+		{CompareUnorderedLines, "2 3\n1\n", "1\n2 3\n", true},
+		{CompareUnorderedLines, "2 3\n1\n", "1\n2 4\n", false},
+	}
+	for _, test := range tests {
+		comparator, err := ParseComparator(test.spec)
+		if err != nil {
+			t.Fatalf("Expect %s to be a valid comparator, but found %s.", test.spec, err)
+		}
+		if result := comparator.Compare([]byte(test.output), []byte(test.answer)); result != test.expect {
+			t.Errorf("Expect %v for %q and %q with %s, but found %v.", test.expect, test.output, test.answer, test.spec, result)
+		}
+	}
+	if _, err := ParseComparator("floats"); err == nil {
+		t.Errorf("Expect an error for an unknown comparator, but found nil.")
+	}
+}
+
+func TestDifference(t *testing.T) {
+	tests := []struct {
+		spec   string
+		output string
+		answer string
+		expect Difference
+	}{
+		{CompareLines, "1 2\n3 4\n", "1 2\n3 5\n", Difference{2, 2, 2, 2, "4", "5"}},
+		{CompareLines, "1 2\n", "1 2\n3\n", Difference{2, 1, 2, 1, "", "3"}},
+		{CompareExact, "1  2\n", "1 2\n", Difference{1, 0, 1, 0, "", ""}},
+		{CompareTokens, "1 2\n3 4\n", "1\n2 3 5\n", Difference{2, 2, 2, 3, "4", "5"}},
+		{CompareLines, "1\n\n\n2\n", "1\n3\n", Difference{4, 1, 2, 1, "2", "3"}},
+		{CompareFloat, "\n1.0\n\n2.0\n", "1.0 3.0\n", Difference{4, 1, 1, 2, "2.0", "3.0"}},
+		{CompareUnorderedLines, "3\n\n1\n2\n", "2\n1\n4\n", Difference{1, 1, 3, 1, "3", "4"}},
+		{CompareUnorderedLines, "2\n1\n", "1\n3\n2\n", Difference{3, 1, 2, 1, "", "3"}},
+	}
+	for _, test := range tests {
+		comparator, _ := ParseComparator(test.spec)
+		difference := comparator.Difference([]byte(test.output), []byte(test.answer))
+		if difference == nil || *difference != test.expect {
+			t.Errorf("Expect %v for %q and %q with %s, but found %v.", test.expect, test.output, test.answer, test.spec, difference)
+		}
+	}
+}
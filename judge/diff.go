@@ -0,0 +1,323 @@
+package judge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Difference is the first place where the output doesn't match the answer.
+// Lines and tokens are counted from 1, Token is 0 if only the whitespace differs.
+// The position in the answer differs from the one in the output only when line breaks are ignored
+// or the lines may be in any order. The lines are always numbered as in the raw output and answer.
+type Difference struct {
+	Line        int    `json:"line"`
+	Token       int    `json:"token"`
+	AnswerLine  int    `json:"answer_line"`
+	AnswerToken int    `json:"answer_token"`
+	Output      string `json:"output"`
+	Answer      string `json:"answer"`
+}
+
+const diffContextLines = 2
+const diffContextTokens = 5
+const diffMaxLineLength = 80
+const diffMaxShownLines = 20
+
+func splitLines(text string) []string {
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// comparedLines are the lines which are compared, numbers holds the line number of each of them
+// in the raw output, as the blank lines are dropped before comparing
+type comparedLines struct {
+	text    []string
+	numbers []int
+}
+
+func numberedLines(lines []string) comparedLines {
+	numbers := make([]int, len(lines))
+	for i := range lines {
+		numbers[i] = i + 1
+	}
+	return comparedLines{lines, numbers}
+}
+
+// plainLines returns the lines of judge.Plain
+func plainLines(raw []byte) (lines comparedLines) {
+	for i, line := range splitLines(string(raw)) {
+		line = strings.TrimSpace(line)
+		if len(line) != 0 {
+			lines.text = append(lines.text, line)
+			lines.numbers = append(lines.numbers, i+1)
+		}
+	}
+	if len(lines.text) == 0 {
+		// Plain of an empty output is a single empty line
+		return comparedLines{[]string{""}, []int{1}}
+	}
+	return
+}
+
+// sortedPlainLines returns the lines of sortedLines, each with its number in the raw output
+func sortedPlainLines(raw []byte) comparedLines {
+	lines := plainLines(raw)
+	order := make([]int, len(lines.text))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return lines.text[order[i]] < lines.text[order[j]]
+	})
+	sorted := comparedLines{make([]string, len(order)), make([]int, len(order))}
+	for i, line := range order {
+		sorted.text[i], sorted.numbers[i] = lines.text[line], lines.numbers[line]
+	}
+	return sorted
+}
+
+// number returns the number in the raw output of the line counted from 1 in the compared lines,
+// the lines after the end are numbered after the last line of the raw output
+func (lines comparedLines) number(line int) int {
+	if line <= 0 {
+		return line
+	}
+	if line <= len(lines.numbers) {
+		return lines.numbers[line-1]
+	}
+	last := 0
+	for _, number := range lines.numbers {
+		if number > last {
+			last = number
+		}
+	}
+	return last + line - len(lines.numbers)
+}
+
+// renumbered returns the difference with the line numbers of the raw output and answer
+func (difference *Difference) renumbered(output, answer comparedLines) *Difference {
+	renumbered := *difference
+	renumbered.Line = output.number(difference.Line)
+	renumbered.AnswerLine = answer.number(difference.AnswerLine)
+	return &renumbered
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+// findDifference compares the outputs line by line and token by token with the given token equality
+func findDifference(output, answer []string, equal func(o, a string) bool) *Difference {
+	for i := 0; i < len(output) || i < len(answer); i++ {
+		outputTokens := strings.Fields(lineAt(output, i))
+		answerTokens := strings.Fields(lineAt(answer, i))
+		for j := 0; j < len(outputTokens) || j < len(answerTokens); j++ {
+			if j >= len(outputTokens) || j >= len(answerTokens) || !equal(outputTokens[j], answerTokens[j]) {
+				difference := &Difference{Line: i + 1, Token: j + 1, AnswerLine: i + 1, AnswerToken: j + 1}
+				if j < len(outputTokens) {
+					difference.Output = outputTokens[j]
+				}
+				if j < len(answerTokens) {
+					difference.Answer = answerTokens[j]
+				}
+				return difference
+			}
+		}
+		if lineAt(output, i) != lineAt(answer, i) {
+			return &Difference{Line: i + 1, AnswerLine: i + 1}
+		}
+	}
+	return nil
+}
+
+type position struct {
+	line  int
+	token int
+}
+
+func tokensWithPositions(lines []string) (tokens []string, positions []position) {
+	for i, line := range lines {
+		for j, token := range strings.Fields(line) {
+			tokens = append(tokens, token)
+			positions = append(positions, position{i + 1, j + 1})
+		}
+	}
+	return
+}
+
+// findTokenDifference ignores the line breaks and reports the position of the first differing token in the output
+func findTokenDifference(output, answer []string, equal func(o, a string) bool) *Difference {
+	outputTokens, outputPositions := tokensWithPositions(output)
+	answerTokens, answerPositions := tokensWithPositions(answer)
+	for i := 0; i < len(outputTokens) || i < len(answerTokens); i++ {
+		if i < len(outputTokens) && i < len(answerTokens) && equal(outputTokens[i], answerTokens[i]) {
+			continue
+		}
+		difference := &Difference{}
+		if i < len(outputTokens) {
+			difference.Line, difference.Token = outputPositions[i].line, outputPositions[i].token
+			difference.Output = outputTokens[i]
+		} else {
+			difference.Line, difference.Token = len(output)+1, 1
+		}
+		if i < len(answerTokens) {
+			difference.AnswerLine, difference.AnswerToken = answerPositions[i].line, answerPositions[i].token
+			difference.Answer = answerTokens[i]
+		} else {
+			difference.AnswerLine, difference.AnswerToken = len(answer)+1, 1
+		}
+		return difference
+	}
+	return nil
+}
+
+func (difference *Difference) String() string {
+	if difference.Token == 0 {
+		return fmt.Sprintf("line %v differs in whitespace", difference.Line)
+	}
+	found := fmt.Sprintf("%q", difference.Output)
+	if difference.Output == "" {
+		found = "nothing"
+	}
+	expected := fmt.Sprintf("%q", difference.Answer)
+	if difference.Answer == "" {
+		expected = "nothing"
+	}
+	if difference.Line != difference.AnswerLine || difference.Token != difference.AnswerToken {
+		return fmt.Sprintf("line %v, token %v (line %v, token %v of the answer): expected %v, found %v", difference.Line, difference.Token, difference.AnswerLine, difference.AnswerToken, expected, found)
+	}
+	return fmt.Sprintf("line %v, token %v: expected %v, found %v", difference.Line, difference.Token, expected, found)
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length] + "..."
+	}
+	return s
+}
+
+// formatTokens returns the line shortened to a few tokens around the highlighted one,
+// both plain (to compute the width) and colored
+func formatTokens(line string, highlight int, c *color.Color) (plain, colored string) {
+	tokens := strings.Fields(line)
+	if highlight < 0 || highlight >= len(tokens) {
+		plain = truncate(line, diffMaxLineLength)
+		colored = plain
+		if highlight >= 0 {
+			plain += " _"
+			colored += " " + c.Sprint("_")
+		}
+		return
+	}
+	begin, end := highlight-diffContextTokens, highlight+diffContextTokens+1
+	var plainParts, coloredParts []string
+	if begin > 0 {
+		plainParts, coloredParts = append(plainParts, "..."), append(coloredParts, "...")
+	} else {
+		begin = 0
+	}
+	if end > len(tokens) {
+		end = len(tokens)
+	}
+	for i := begin; i < end; i++ {
+		token := truncate(tokens[i], diffMaxLineLength)
+		plainParts = append(plainParts, token)
+		if i == highlight {
+			coloredParts = append(coloredParts, c.Sprint(token))
+		} else {
+			coloredParts = append(coloredParts, token)
+		}
+	}
+	if end < len(tokens) {
+		plainParts, coloredParts = append(plainParts, "..."), append(coloredParts, "...")
+	}
+	return strings.Join(plainParts, " "), strings.Join(coloredParts, " ")
+}
+
+type diffLine struct {
+	number  int
+	plain   string
+	colored string
+}
+
+func contextLines(lines comparedLines, line, token int, c *color.Color) (result []diffLine) {
+	first := line - 1 - diffContextLines
+	if first < 0 {
+		first = 0
+	}
+	for i := first; i < len(lines.text) && i <= line-1+diffContextLines; i++ {
+		highlight := -1
+		if i == line-1 && token > 0 {
+			highlight = token - 1
+		}
+		plain, colored := formatTokens(lines.text[i], highlight, c)
+		result = append(result, diffLine{lines.number(i + 1), plain, colored})
+	}
+	if line > len(lines.text) {
+		result = append(result, diffLine{lines.number(line), "<EOF>", c.Sprint("<EOF>")})
+	}
+	return
+}
+
+// formatDiff shows the lines around the difference, whose lines are counted in the compared lines
+func formatDiff(output, answer comparedLines, difference *Difference, sideBySide bool) (diff string) {
+	red, green, cyan := color.New(color.FgRed), color.New(color.FgGreen), color.New(color.FgCyan)
+	diff += cyan.Sprintf("first difference at ") + difference.renumbered(output, answer).String() + "\n"
+	outputLines := contextLines(output, difference.Line, difference.Token, red)
+	answerLines := contextLines(answer, difference.AnswerLine, difference.AnswerToken, green)
+	if !sideBySide {
+		diff += cyan.Sprintf("-----Output-----\n")
+		for _, line := range outputLines {
+			diff += fmt.Sprintf("%5d| %v\n", line.number, line.colored)
+		}
+		diff += cyan.Sprintf("-----Answer-----\n")
+		for _, line := range answerLines {
+			diff += fmt.Sprintf("%5d| %v\n", line.number, line.colored)
+		}
+		return
+	}
+	width := len("Output")
+	for _, line := range outputLines {
+		if len(line.plain) > width {
+			width = len(line.plain)
+		}
+	}
+	diff += cyan.Sprintf("%7v%-*v %7v%v\n", "", width, "Output", "", "Answer")
+	for i := 0; i < len(outputLines) || i < len(answerLines); i++ {
+		left := strings.Repeat(" ", width+7)
+		if i < len(outputLines) {
+			line := outputLines[i]
+			left = fmt.Sprintf("%5d| %v%v", line.number, line.colored, strings.Repeat(" ", width-len(line.plain)))
+		}
+		right := ""
+		if i < len(answerLines) {
+			right = fmt.Sprintf("%5d| %v", answerLines[i].number, answerLines[i].colored)
+		}
+		diff += left + " " + right + "\n"
+	}
+	return
+}
+
+// formatOutputAndAnswer shows the beginning of the output and the answer when there is no single difference to point at
+func formatOutputAndAnswer(output, answer string) (diff string) {
+	show := func(text string) (shown string) {
+		lines := splitLines(text)
+		for i, line := range lines {
+			if i == diffMaxShownLines {
+				return shown + fmt.Sprintf("... (%v more lines)\n", len(lines)-diffMaxShownLines)
+			}
+			shown += truncate(line, diffMaxLineLength*2) + "\n"
+		}
+		return
+	}
+	diff += color.New(color.FgCyan).Sprintf("-----Output-----\n")
+	diff += show(output)
+	diff += color.New(color.FgCyan).Sprintf("-----Answer-----\n")
+	diff += show(answer)
+	return
+}
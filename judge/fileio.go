@@ -0,0 +1,72 @@
+package judge
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Arapak/sio-tool/util"
+)
+
+// FileIO makes the program read the input from and write the output to files in the folder it's run in,
+// an empty name means the standard input or output
+type FileIO struct {
+	Input  string
+	Output string
+}
+
+// run gives the test to the program in the input file and returns the content of the output file as its output,
+// the program is run in a temporary folder so the files of the user are never touched
+func (fileIO *FileIO) run(options *Options, command string, input []byte) (processInfo ProcessInfo, err error) {
+	dir, err := os.MkdirTemp("", "st-fileio-")
+	if err != nil {
+		return ProcessInfo{Status: INT}, err
+	}
+	defer os.RemoveAll(dir)
+	if fileIO.Input != "" {
+		if err = os.WriteFile(filepath.Join(dir, fileIO.Input), input, 0644); err != nil {
+			return ProcessInfo{Status: INT}, err
+		}
+	}
+	command, err = absoluteCommand(command)
+	if err != nil {
+		return ProcessInfo{Status: INT}, err
+	}
+	runOptions := Options{}
+	if options != nil {
+		runOptions = *options
+	}
+	runOptions.dir = dir
+	processInfo, err = runOptions.Run(command, bytes.NewReader(input))
+	if err != nil || processInfo.Status != OK || fileIO.Output == "" {
+		return
+	}
+	if processInfo.Output, err = os.ReadFile(filepath.Join(dir, fileIO.Output)); os.IsNotExist(err) {
+		// a program which didn't create the output file printed nothing
+		processInfo.Output, err = []byte{}, nil
+	}
+	if err != nil {
+		processInfo.Status = INT
+	}
+	return
+}
+
+// absoluteCommand turns the arguments of the command which are paths in the current folder into absolute ones,
+// so that it can be run in another folder
+func absoluteCommand(command string) (string, error) {
+	cmds := util.SplitCmd(command)
+	for i, arg := range cmds {
+		if !filepath.IsAbs(arg) && !strings.HasPrefix(arg, "-") {
+			if _, err := os.Stat(arg); err == nil {
+				abs, err := filepath.Abs(arg)
+				if err != nil {
+					return "", err
+				}
+				arg = abs
+			}
+		}
+		cmds[i] = `"` + arg + `"`
+	}
+	return strings.Join(cmds, " "), nil
+}
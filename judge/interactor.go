@@ -0,0 +1,147 @@
+package judge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Arapak/sio-tool/util"
+	"github.com/fatih/color"
+)
+
+// Interactor is a program talking with the solution through its standard input and output.
+// It is invoked as `interactor in out [ans]` and reports the verdict like a testlib checker.
+type Interactor struct {
+	Command string
+}
+
+// transcript records the communication between the solution and the interactor line by line
+type transcript struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	pending map[string][]byte
+}
+
+type transcriptWriter struct {
+	t      *transcript
+	prefix string
+}
+
+func newTranscript() *transcript {
+	return &transcript{pending: make(map[string][]byte)}
+}
+
+func (w transcriptWriter) Write(p []byte) (int, error) {
+	w.t.mu.Lock()
+	defer w.t.mu.Unlock()
+	pending := append(w.t.pending[w.prefix], p...)
+	for {
+		i := bytes.IndexByte(pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.t.buf.WriteString(w.prefix)
+		w.t.buf.Write(pending[:i+1])
+		pending = pending[i+1:]
+	}
+	w.t.pending[w.prefix] = pending
+	return len(p), nil
+}
+
+func (t *transcript) writer(prefix string) io.Writer {
+	return transcriptWriter{t, prefix}
+}
+
+func (t *transcript) Bytes() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for prefix, pending := range t.pending {
+		if len(pending) > 0 {
+			t.buf.WriteString(prefix)
+			t.buf.Write(util.AddNewLine(pending))
+			t.pending[prefix] = nil
+		}
+	}
+	return t.buf.Bytes()
+}
+
+const ErrorInteractorTimeout = "the interactor exceeded the time limit"
+
+const solutionPrefix = "> "
+const interactorPrefix = "< "
+
+func (interactor *Interactor) Judge(inPath, ansPath, testID, command string, options *Options) Verdict {
+	output, err := os.CreateTemp(os.TempDir(), "st-interactor-")
+	if err != nil {
+		return Verdict{Status: INT, Err: err}
+	}
+	output.Close()
+	defer os.Remove(output.Name())
+
+	toInteractorReader, toInteractorWriter, err := os.Pipe()
+	if err != nil {
+		return Verdict{Status: INT, Err: err}
+	}
+	toSolutionReader, toSolutionWriter, err := os.Pipe()
+	if err != nil {
+		toInteractorReader.Close()
+		toInteractorWriter.Close()
+		return Verdict{Status: INT, Err: err}
+	}
+
+	interactorCommand := fmt.Sprintf(`%v "%v" "%v"`, interactor.Command, inPath, output.Name())
+	if util.FileExists(ansPath) {
+		interactorCommand += fmt.Sprintf(` "%v"`, ansPath)
+	}
+
+	log := newTranscript()
+	// the interactor is killed like the solution, so that it can't hang waiting for it
+	limits := &Limits{TimeLimitInSeconds: options.timeLimitInSeconds()}
+
+	var interactorInfo ProcessInfo
+	var interactorErr error
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		interactorInfo, interactorErr = runProcess(interactorCommand, toInteractorReader, io.MultiWriter(toSolutionWriter, log.writer(interactorPrefix)), nil, limits)
+		toSolutionWriter.Close()
+		toInteractorReader.Close()
+	}()
+
+	processInfo, err := options.run(command, toSolutionReader, io.MultiWriter(toInteractorWriter, log.writer(solutionPrefix)))
+	toInteractorWriter.Close()
+	toSolutionReader.Close()
+	wg.Wait()
+
+	var verdict Verdict
+	if err != nil || processInfo.Status != OK {
+		verdict = Verdict{Status: processInfo.Status, TimeInSeconds: processInfo.TimeInSeconds, MemoryInMegabytes: processInfo.MemoryInMegabytes, Err: err, Stderr: processInfo.Stderr}
+	} else if interactorInfo.Status == TLE {
+		verdict = Verdict{Status: INT, TimeInSeconds: processInfo.TimeInSeconds, MemoryInMegabytes: processInfo.MemoryInMegabytes, Err: errors.New(ErrorInteractorTimeout)}
+	} else {
+		result, err := parseTestlibResult(interactorInfo.Stderr, interactorErr)
+		if err != nil {
+			verdict = Verdict{Status: INT, TimeInSeconds: processInfo.TimeInSeconds, MemoryInMegabytes: processInfo.MemoryInMegabytes, Err: err}
+		} else {
+			verdict = newVerdict(testID, result.Status, result.Message, result.Points, "", processInfo)
+		}
+	}
+	if verdict.Status != OK {
+		// named like the saved stderr, a name ending with .in would be taken for another test
+		transcriptPath := strings.TrimSuffix(inPath, filepath.Ext(inPath)) + ".transcript"
+		if err := os.WriteFile(transcriptPath, log.Bytes(), 0644); err != nil {
+			verdict.Message += color.RedString("cannot save the transcript: %v\n", err.Error())
+		} else if verdict.Err != nil {
+			verdict.Err = fmt.Errorf("%w (transcript saved to %v)", verdict.Err, transcriptPath)
+		} else {
+			verdict.Message += color.CyanString("transcript saved to %v\n", transcriptPath)
+		}
+	}
+	return verdict
+}
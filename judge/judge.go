@@ -1,32 +1,110 @@
 package judge
 
 import (
+	"bytes"
+	"io"
 	"os"
 	"strings"
 )
 
-func Judge(inPath, ansPath, sampleID, command string, oiejqOptions *OiejqOptions) Verdict {
-	input, err := os.Open(inPath)
+type Options struct {
+	Oiejq      *OiejqOptions
+	Cgroup     *CgroupOptions
+	Limits     *Limits
+	Checker    *Checker
+	Comparator *Comparator
+	SideBySide bool
+	Interactor *Interactor
+	FileIO     *FileIO
+	// dir is the folder the program is run in, the current one when empty
+	dir string
+}
+
+func (options *Options) run(command string, input io.Reader, output io.Writer) (ProcessInfo, error) {
+	if options != nil && options.Oiejq != nil {
+		return runProcessWithOiejq(command, options.dir, input, output, options.Oiejq)
+	}
+	if options != nil && options.Cgroup != nil {
+		return runProcessInCgroup(command, options.dir, input, output, options.Cgroup)
+	}
+	if options == nil {
+		return runProcess(command, input, output, nil, nil)
+	}
+	return runProcessInDir(command, options.dir, input, output, nil, options.Limits)
+}
+
+// timeLimitInSeconds returns the time limit of the solution, the programs judging it are given the same one
+func (options *Options) timeLimitInSeconds() float64 {
+	timeLimit := defaultTimeLimit
+	switch {
+	case options == nil:
+	case options.Oiejq != nil:
+		timeLimit = options.Oiejq.TimeLimitInSeconds
+	case options.Cgroup != nil:
+		return options.Cgroup.Limits.TimeLimitInSeconds
+	case options.Limits != nil:
+		return options.Limits.TimeLimitInSeconds
+	}
+	limits, err := ParseLimits(timeLimit, "")
 	if err != nil {
-		return Verdict{INT, 0, 0, "", err}
+		limits, _ = ParseLimits(defaultTimeLimit, "")
+	}
+	return limits.TimeLimitInSeconds
+}
+
+func (options *Options) comparator() *Comparator {
+	if options == nil {
+		return nil
+	}
+	return options.Comparator
+}
+
+func (options *Options) Run(command string, input io.Reader) (ProcessInfo, error) {
+	return options.run(command, input, nil)
+}
+
+// RunOn runs the program on the test, through the files of the problem when it uses file input/output
+func (options *Options) RunOn(command string, input []byte) (ProcessInfo, error) {
+	if options != nil && options.FileIO != nil {
+		return options.FileIO.run(options, command, input)
+	}
+	return options.Run(command, bytes.NewReader(input))
+}
+
+func Judge(inPath, ansPath, sampleID, command string, options *Options) Verdict {
+	if options != nil && options.Interactor != nil {
+		return options.Interactor.Judge(inPath, ansPath, sampleID, command, options)
 	}
-	defer input.Close()
 
 	var processInfo ProcessInfo
-	if oiejqOptions != nil {
-		processInfo, err = RunProcessWithOiejq(command, input, oiejqOptions)
+	var err error
+	if options != nil && options.FileIO != nil {
+		input, readErr := os.ReadFile(inPath)
+		if readErr != nil {
+			return Verdict{Status: INT, Err: readErr}
+		}
+		processInfo, err = options.FileIO.run(options, command, input)
 	} else {
-		processInfo, err = RunProcess(command, input, nil)
+		input, openErr := os.Open(inPath)
+		if openErr != nil {
+			return Verdict{Status: INT, Err: openErr}
+		}
+		defer input.Close()
+		processInfo, err = options.Run(command, input)
 	}
 	if err != nil || processInfo.Status != OK {
-		return Verdict{processInfo.Status, processInfo.TimeInSeconds, processInfo.MemoryInMegabytes, "", err}
+		return Verdict{Status: processInfo.Status, TimeInSeconds: processInfo.TimeInSeconds, MemoryInMegabytes: processInfo.MemoryInMegabytes, Err: err, Stderr: processInfo.Stderr}
+	}
+
+	if options != nil && options.Checker != nil {
+		return options.Checker.Verdict(sampleID, inPath, ansPath, processInfo)
 	}
 
 	b, err := os.ReadFile(ansPath)
 	if err != nil {
-		return Verdict{INT, 0, 0, "", err}
+		return Verdict{Status: INT, Err: err}
 	}
-	return GenerateVerdict(sampleID, Plain(b), processInfo)
+	return options.comparator().Verdict(sampleID, b, processInfo, options != nil && options.SideBySide)
 }
 
 func ExtractTaskName(file string) (task string) {
@@ -0,0 +1,65 @@
+package judge
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Limits are enforced by RunProcess when the program is run without sio2jail
+type Limits struct {
+	TimeLimitInSeconds     float64
+	MemoryLimitInMegabytes float64
+	// NoMemoryLimit turns the memory limit off, e.g. for programs built with AddressSanitizer
+	NoMemoryLimit bool
+}
+
+// the program is killed after wallTimeLimitMultiplier * time limit + wallTimeLimitMargin of real time,
+// so that sleeping or waiting programs don't hang forever
+const wallTimeLimitMultiplier = 2
+const wallTimeLimitMargin = time.Second
+
+func (limits *Limits) wallTimeLimit() time.Duration {
+	return time.Duration(limits.TimeLimitInSeconds*wallTimeLimitMultiplier*float64(time.Second)) + wallTimeLimitMargin
+}
+
+func (limits *Limits) memoryLimitInBytes() uint64 {
+	return uint64(limits.MemoryLimitInMegabytes * 1024 * 1024)
+}
+
+// memoryLimited tells if the memory limit is enforced, it isn't when none was given
+// and then only the peak usage is measured
+func (limits *Limits) memoryLimited() bool {
+	return !limits.NoMemoryLimit && limits.MemoryLimitInMegabytes > 0
+}
+
+// ParseLimits parses the limits given by the user or the metadata, an empty memory limit stays unknown
+func ParseLimits(timeLimit, memoryLimit string) (limits Limits, err error) {
+	if timeLimit == "" {
+		timeLimit = defaultTimeLimit
+	}
+	limits.TimeLimitInSeconds, err = strconv.ParseFloat(timeLimit, 64)
+	if err != nil || limits.TimeLimitInSeconds <= 0 {
+		return limits, fmt.Errorf("invalid time limit: %v", timeLimit)
+	}
+	if memoryLimit == "" {
+		return
+	}
+	limits.MemoryLimitInMegabytes, err = strconv.ParseFloat(memoryLimit, 64)
+	if err != nil || limits.MemoryLimitInMegabytes <= 0 {
+		return limits, fmt.Errorf("invalid memory limit: %v", memoryLimit)
+	}
+	return
+}
+
+// String shows the limits as "1s, 256MB", a limit equal to zero is unknown
+func (limits Limits) String() string {
+	timeLimit, memoryLimit := "?", "?"
+	if limits.TimeLimitInSeconds > 0 {
+		timeLimit = strconv.FormatFloat(limits.TimeLimitInSeconds, 'f', -1, 64) + "s"
+	}
+	if limits.MemoryLimitInMegabytes > 0 {
+		memoryLimit = strconv.FormatFloat(limits.MemoryLimitInMegabytes, 'f', -1, 64) + "MB"
+	}
+	return timeLimit + ", " + memoryLimit
+}
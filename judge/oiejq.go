@@ -103,7 +103,11 @@ func readOiejqOutput(path string) (processInfo ProcessInfo, err error) {
 	return
 }
 
-func RunProcessWithOiejq(command string, input io.Reader, oiejqOptions *OiejqOptions) (oiejqProcessInfo ProcessInfo, err error) {
+func RunProcessWithOiejq(command string, input io.Reader, oiejqOptions *OiejqOptions) (ProcessInfo, error) {
+	return runProcessWithOiejq(command, "", input, nil, oiejqOptions)
+}
+
+func runProcessWithOiejq(command, dir string, input io.Reader, output io.Writer, oiejqOptions *OiejqOptions) (oiejqProcessInfo ProcessInfo, err error) {
 	oiejqResults, err := os.CreateTemp(os.TempDir(), "sio2jail-")
 	if err != nil {
 		oiejqProcessInfo.Status = INT
@@ -122,7 +126,7 @@ func RunProcessWithOiejq(command string, input io.Reader, oiejqOptions *OiejqOpt
 	}
 
 	oiejqCommand := fmt.Sprintf(sio2jailCommand, sio2jailPath, oiejqOptions.TimeLimitInSeconds, options, oiejqOptions.MemorylimitInMegaBytes, command, oiejqResults.Name())
-	processInfo, processErr := RunProcess(oiejqCommand, input, oiejqResults)
+	processInfo, processErr := runProcessInDir(oiejqCommand, dir, input, output, oiejqResults, nil)
 	oiejqProcessInfo, err = readOiejqOutput(oiejqResults.Name())
 	oiejqProcessInfo.Output = processInfo.Output
 	oiejqProcessInfo.Stderr = processInfo.Stderr
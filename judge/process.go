@@ -6,9 +6,9 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/Arapak/sio-tool/util"
-	"github.com/shirou/gopsutil/process"
 )
 
 func Plain(raw []byte) string {
@@ -34,47 +34,117 @@ type ProcessInfo struct {
 }
 
 func RunProcess(command string, input io.Reader, extrafile *os.File) (ProcessInfo, error) {
+	return runProcess(command, input, nil, extrafile, nil)
+}
+
+// the resident memory of a program with a memory limit is checked this often
+const memoryPollInterval = 5 * time.Millisecond
+
+// memoryLimiter kills the program as soon as its resident memory exceeds the limit. Unlike an address space
+// limit it doesn't break programs reserving much more memory than they use, like Go, Java or ASan binaries.
+type memoryLimiter struct {
+	done     chan struct{}
+	exceeded chan bool
+}
+
+func limitMemory(cmd *exec.Cmd, limitInBytes uint64) *memoryLimiter {
+	limiter := &memoryLimiter{make(chan struct{}), make(chan bool, 1)}
+	go func() {
+		ticker := time.NewTicker(memoryPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-limiter.done:
+				limiter.exceeded <- false
+				return
+			case <-ticker.C:
+				if memory, err := residentMemory(cmd.Process.Pid); err == nil && memory > limitInBytes {
+					_ = killProcessGroup(cmd)
+					limiter.exceeded <- true
+					return
+				}
+			}
+		}
+	}()
+	return limiter
+}
+
+// stop ends watching the program, which has already finished, and tells if it was killed for exceeding the limit
+func (limiter *memoryLimiter) stop() bool {
+	close(limiter.done)
+	return <-limiter.exceeded
+}
+
+// runProcess writes the standard output to output, or returns it in ProcessInfo.Output if output is nil
+func runProcess(command string, input io.Reader, output io.Writer, extrafile *os.File, limits *Limits) (ProcessInfo, error) {
+	return runProcessInDir(command, "", input, output, extrafile, limits)
+}
+
+// runProcessInDir runs the program in the given folder, the current one when empty
+func runProcessInDir(command, dir string, input io.Reader, output io.Writer, extrafile *os.File, limits *Limits) (ProcessInfo, error) {
 	var o bytes.Buffer
-	output := io.Writer(&o)
+	if output == nil {
+		output = io.Writer(&o)
+	}
 	var e bytes.Buffer
 	stderr := io.Writer(&e)
 
 	cmds := util.SplitCmd(command)
 
 	cmd := exec.Command(cmds[0], cmds[1:]...)
+	cmd.Dir = dir
 	cmd.Stdin = input
 	cmd.Stdout = output
 	cmd.Stderr = stderr
 	if extrafile != nil {
 		cmd.ExtraFiles = append(cmd.ExtraFiles, extrafile)
 	}
+	setProcessGroup(cmd)
 	if err := cmd.Start(); err != nil {
 		return ProcessInfo{RE, 0, 0, []byte{}, []byte{}}, err
 	}
+	memory := watchMemory(cmd)
+	defer memory.close()
 
-	pid := int32(cmd.Process.Pid)
-	maxMemory := uint64(0)
-	ch := make(chan error)
+	var limiter *memoryLimiter
+	if limits != nil && limits.memoryLimited() {
+		limiter = limitMemory(cmd, limits.memoryLimitInBytes())
+	}
+	var timeout <-chan time.Time
+	if limits != nil {
+		timer := time.NewTimer(limits.wallTimeLimit())
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	ch := make(chan error, 1)
 	go func() {
 		ch <- cmd.Wait()
 	}()
-	running := true
-	for running {
-		select {
-		case err := <-ch:
-			if err != nil {
-				return ProcessInfo{RE, 0, float64(maxMemory) / (1024.0 * 1024.0), []byte{}, e.Bytes()}, err
-			}
-			running = false
-		default:
-			p, err := process.NewProcess(pid)
-			if err == nil {
-				m, err := p.MemoryInfo()
-				if err == nil && m.RSS > maxMemory {
-					maxMemory = m.RSS
-				}
-			}
+	timedOut := false
+	var err error
+	select {
+	case err = <-ch:
+	case <-timeout:
+		timedOut = true
+		_ = killProcessGroup(cmd)
+		err = <-ch
+	}
+
+	memoryExceeded := limiter != nil && limiter.stop()
+
+	timeInSeconds := (cmd.ProcessState.UserTime() + cmd.ProcessState.SystemTime()).Seconds()
+	memoryInMegabytes := float64(memory.peak(cmd.ProcessState)) / (1024.0 * 1024.0)
+	if limits != nil {
+		if timedOut || timeInSeconds > limits.TimeLimitInSeconds {
+			return ProcessInfo{TLE, timeInSeconds, memoryInMegabytes, []byte{}, e.Bytes()}, nil
+		}
+		if memoryExceeded || (limits.memoryLimited() && memoryInMegabytes > limits.MemoryLimitInMegabytes) {
+			return ProcessInfo{MLE, timeInSeconds, memoryInMegabytes, []byte{}, e.Bytes()}, nil
 		}
 	}
-	return ProcessInfo{OK, cmd.ProcessState.UserTime().Seconds(), float64(maxMemory) / (1024.0 * 1024.0), o.Bytes(), e.Bytes()}, nil
+	if err != nil {
+		return ProcessInfo{RE, timeInSeconds, memoryInMegabytes, []byte{}, e.Bytes()}, err
+	}
+	return ProcessInfo{OK, timeInSeconds, memoryInMegabytes, o.Bytes(), e.Bytes()}, nil
 }
@@ -0,0 +1,33 @@
+package judge
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Maxrss is reported in kilobytes
+const maxrssUnit = 1024
+
+// the tested program shouldn't outlive st, for example when it is interrupted with ctrl+c
+func setParentDeathSignal(attr *syscall.SysProcAttr) {
+	attr.Pdeathsig = syscall.SIGKILL
+}
+
+// residentMemory reads the resident set size of the running process
+func residentMemory(pid int) (uint64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%v/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "VmRSS:" {
+			kilobytes, err := strconv.ParseUint(fields[1], 10, 64)
+			return kilobytes * 1024, err
+		}
+	}
+	return 0, errors.New("no VmRSS in the status of the process")
+}
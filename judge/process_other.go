@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !windows
+
+package judge
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Maxrss is reported in kilobytes
+const maxrssUnit = 1024
+
+func setParentDeathSignal(attr *syscall.SysProcAttr) {}
+
+// residentMemory isn't available, the memory limit is only checked against the peak usage after the run
+func residentMemory(pid int) (uint64, error) {
+	return 0, errors.New("the resident memory can't be read")
+}
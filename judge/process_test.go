@@ -0,0 +1,45 @@
+package judge
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// allocateEnv makes the test binary allocate and touch that many megabytes instead of running the tests
+const allocateEnv = "ST_TEST_ALLOCATE_MB"
+
+func TestMain(m *testing.M) {
+	if megabytes, err := strconv.Atoi(os.Getenv(allocateEnv)); err == nil {
+		memory := make([]byte, megabytes*1024*1024)
+		for i := range memory {
+			memory[i] = 1
+		}
+		fmt.Println(len(memory))
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestMemoryLimit(t *testing.T) {
+	tests := []struct {
+		allocate int
+		limit    float64
+		expect   VerdictStatus
+	}{
+		{16, 256, OK},
+		{256, 64, MLE},
+	}
+	for _, test := range tests {
+		t.Setenv(allocateEnv, strconv.Itoa(test.allocate))
+		limits := &Limits{TimeLimitInSeconds: 10, MemoryLimitInMegabytes: test.limit}
+		info, err := runProcess(fmt.Sprintf("%q", os.Args[0]), nil, nil, nil, limits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Status != test.expect {
+			t.Errorf("Expect %v, but found %v.", test.expect, info.Status)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package judge
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	setParentDeathSignal(cmd.SysProcAttr)
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// memoryWatcher reads the peak memory usage reported by wait4
+type memoryWatcher struct{}
+
+func watchMemory(cmd *exec.Cmd) memoryWatcher {
+	return memoryWatcher{}
+}
+
+func (memoryWatcher) peak(state *os.ProcessState) uint64 {
+	if rusage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		return uint64(rusage.Maxrss) * maxrssUnit
+	}
+	return 0
+}
+
+func (memoryWatcher) close() {}
@@ -0,0 +1,67 @@
+package judge
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+var procGetProcessMemoryInfo = syscall.NewLazyDLL("psapi.dll").NewProc("GetProcessMemoryInfo")
+
+type processMemoryCounters struct {
+	Cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// memoryWatcher keeps a handle to the process, because os.Process releases its own after Wait
+type memoryWatcher struct {
+	handle syscall.Handle
+}
+
+func watchMemory(cmd *exec.Cmd) memoryWatcher {
+	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		return memoryWatcher{}
+	}
+	return memoryWatcher{handle}
+}
+
+func (watcher memoryWatcher) peak(state *os.ProcessState) uint64 {
+	if watcher.handle == 0 {
+		return 0
+	}
+	var counters processMemoryCounters
+	counters.Cb = uint32(unsafe.Sizeof(counters))
+	r, _, _ := procGetProcessMemoryInfo.Call(uintptr(watcher.handle), uintptr(unsafe.Pointer(&counters)), uintptr(counters.Cb))
+	if r == 0 {
+		return 0
+	}
+	return uint64(counters.PeakWorkingSetSize)
+}
+
+func (watcher memoryWatcher) close() {
+	if watcher.handle != 0 {
+		_ = syscall.CloseHandle(watcher.handle)
+	}
+}
+
+// residentMemory isn't available, the memory limit is only checked against the peak usage after the run
+func residentMemory(pid int) (uint64, error) {
+	return 0, errors.New("the resident memory can't be read")
+}
@@ -34,6 +34,10 @@ type Verdict struct {
 	MemoryInMegabytes float64
 	Message           string
 	Err               error
+	CheckerMessage    string
+	Points            float64
+	Difference        *Difference
+	Stderr            []byte
 }
 
 func ParseMemory(memory float64) string {
@@ -45,21 +49,24 @@ func ParseMemory(memory float64) string {
 	return fmt.Sprintf("%.0fB", memory*1024.0*1024.0)
 }
 
-func GenerateVerdict(testID, answer string, processInfo ProcessInfo) Verdict {
+func newVerdict(testID string, status VerdictStatus, checkerMessage string, points float64, diff string, processInfo ProcessInfo) Verdict {
 	state := ""
-	diff := ""
-	var status VerdictStatus
-	output := Plain(processInfo.Output)
-	if output == answer {
-		status = OK
+	if status == OK {
 		state = color.New(color.FgGreen).Sprintf("Passed #%v", testID)
+		if points < 100 {
+			state += color.New(color.FgYellow).Sprintf(" (%.1f points)", points)
+		}
 	} else {
-		status = WA
 		state = color.New(color.FgRed).Sprintf("Failed #%v", testID)
-		diff += color.New(color.FgCyan).Sprintf("-----Output-----\n")
-		diff += output + "\n"
-		diff += color.New(color.FgCyan).Sprintf("-----Answer-----\n")
-		diff += answer + "\n"
 	}
-	return Verdict{status, processInfo.TimeInSeconds, processInfo.MemoryInMegabytes, fmt.Sprintf("%v ... %.3fs %v\n%v", state, processInfo.TimeInSeconds, ParseMemory(processInfo.MemoryInMegabytes), diff), nil}
+	if checkerMessage != "" {
+		diff = color.New(color.FgCyan).Sprintf("checker: ") + checkerMessage + "\n" + diff
+	}
+	message := fmt.Sprintf("%v ... %.3fs %v\n%v", state, processInfo.TimeInSeconds, ParseMemory(processInfo.MemoryInMegabytes), diff)
+	return Verdict{status, processInfo.TimeInSeconds, processInfo.MemoryInMegabytes, message, nil, checkerMessage, points, nil, processInfo.Stderr}
+}
+
+func GenerateVerdict(testID, answer string, processInfo ProcessInfo) Verdict {
+	var comparator *Comparator
+	return comparator.Verdict(testID, []byte(answer), processInfo, false)
 }
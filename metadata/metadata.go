@@ -0,0 +1,101 @@
+package metadata
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/Arapak/sio-tool/codeforces_client"
+	"github.com/Arapak/sio-tool/judge"
+	"github.com/Arapak/sio-tool/sio_client"
+	"github.com/Arapak/sio-tool/szkopul_client"
+)
+
+// FileName is the name of the file describing the problem, stored in the problem's folder
+const FileName = ".st-problem.json"
+
+const (
+	StandardIO = "standard"
+	FileIO     = "file"
+)
+
+// Problem holds what is known about the problem. The limits and the checker are used as defaults by the test commands,
+// the checker is a path relative to the problem's folder.
+type Problem struct {
+	Name                   string                  `json:"name,omitempty"`
+	Link                   string                  `json:"link,omitempty"`
+	Codeforces             *codeforces_client.Info `json:"codeforces,omitempty"`
+	Sio                    *sio_client.Info        `json:"sio,omitempty"`
+	Szkopul                *szkopul_client.Info    `json:"szkopul,omitempty"`
+	TimeLimitInSeconds     float64                 `json:"time_limit,omitempty"`
+	MemoryLimitInMegabytes float64                 `json:"memory_limit,omitempty"`
+	IO                     string                  `json:"io,omitempty"`
+	InputFile              string                  `json:"input_file,omitempty"`
+	OutputFile             string                  `json:"output_file,omitempty"`
+	Checker                string                  `json:"checker,omitempty"`
+	Comparator             string                  `json:"comparator,omitempty"`
+}
+
+// FileIO returns the files used by a problem with file input/output, nil if they aren't known
+func (problem *Problem) FileIO() *judge.FileIO {
+	if problem.IO != FileIO || (problem.InputFile == "" && problem.OutputFile == "") {
+		return nil
+	}
+	return &judge.FileIO{Input: problem.InputFile, Output: problem.OutputFile}
+}
+
+// Limits returns the limits of the problem, the unknown ones are zero
+func (problem *Problem) Limits() judge.Limits {
+	return judge.Limits{TimeLimitInSeconds: problem.TimeLimitInSeconds, MemoryLimitInMegabytes: problem.MemoryLimitInMegabytes}
+}
+
+func IOMode(standardIO bool) string {
+	if standardIO {
+		return StandardIO
+	}
+	return FileIO
+}
+
+// Load reads the metadata of the problem in dir. A missing file gives empty metadata.
+func Load(dir string) (problem *Problem, err error) {
+	problem = &Problem{}
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if os.IsNotExist(err) {
+		return problem, nil
+	}
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, problem)
+	return
+}
+
+func (problem *Problem) Save(dir string) error {
+	data, err := json.MarshalIndent(problem, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, FileName), data, 0644)
+}
+
+// Update saves the metadata from parsing, keeping the checker and the comparator chosen by the user
+// and the limits which weren't found in the statement
+func (problem *Problem) Update(dir string) error {
+	old, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	if problem.TimeLimitInSeconds == 0 {
+		problem.TimeLimitInSeconds = old.TimeLimitInSeconds
+	}
+	if problem.MemoryLimitInMegabytes == 0 {
+		problem.MemoryLimitInMegabytes = old.MemoryLimitInMegabytes
+	}
+	if problem.Checker == "" {
+		problem.Checker = old.Checker
+	}
+	if problem.Comparator == "" {
+		problem.Comparator = old.Comparator
+	}
+	return problem.Save(dir)
+}
@@ -0,0 +1,131 @@
+package polygon_package
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const DescriptorFileName = "package.xml"
+
+const MainSolutionTag = "main"
+
+// Package is the package.xml of a Codeforces Polygon package, only with what st uses.
+// Time limits are in milliseconds, memory limits in bytes.
+type Package struct {
+	ShortName string    `xml:"short-name,attr"`
+	Names     []Name    `xml:"names>name"`
+	Testsets  []Testset `xml:"judging>testset"`
+	// the programs used by the generation scripts
+	Executables []Source   `xml:"files>executables>executable>source"`
+	Checker     Source     `xml:"assets>checker>source"`
+	Validators  []Source   `xml:"assets>validators>validator>source"`
+	Solutions   []Solution `xml:"assets>solutions>solution"`
+}
+
+type Name struct {
+	Language string `xml:"language,attr"`
+	Value    string `xml:"value,attr"`
+}
+
+type Testset struct {
+	Name              string `xml:"name,attr"`
+	TimeLimit         int    `xml:"time-limit"`
+	MemoryLimit       int    `xml:"memory-limit"`
+	InputPathPattern  string `xml:"input-path-pattern"`
+	AnswerPathPattern string `xml:"answer-path-pattern"`
+	Tests             []Test `xml:"tests>test"`
+}
+
+type Test struct {
+	Method string  `xml:"method,attr"`
+	Cmd    string  `xml:"cmd,attr"`
+	Sample bool    `xml:"sample,attr"`
+	Group  string  `xml:"group,attr"`
+	Points float64 `xml:"points,attr"`
+}
+
+type Source struct {
+	Path string `xml:"path,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type Solution struct {
+	Tag    string `xml:"tag,attr"`
+	Source Source `xml:"source"`
+}
+
+// Load reads package.xml of the package, a nil package means that it isn't a Polygon package
+func Load(packagePath string) (pkg *Package, err error) {
+	data, err := os.ReadFile(filepath.Join(packagePath, DescriptorFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+	pkg = &Package{}
+	if err = xml.Unmarshal(data, pkg); err != nil {
+		return nil, fmt.Errorf("%v: %v", DescriptorFileName, err)
+	}
+	if len(pkg.Testsets) == 0 {
+		return nil, fmt.Errorf("%v: there is no testset", DescriptorFileName)
+	}
+	return
+}
+
+// Name returns the English name of the problem if there is one
+func (pkg *Package) Name() string {
+	for _, name := range pkg.Names {
+		if name.Language == "english" {
+			return name.Value
+		}
+	}
+	if len(pkg.Names) > 0 {
+		return pkg.Names[0].Value
+	}
+	return pkg.ShortName
+}
+
+// Testset returns the testset the problem is judged on, the one named tests if there are more
+func (pkg *Package) Testset() *Testset {
+	for i := range pkg.Testsets {
+		if pkg.Testsets[i].Name == "tests" {
+			return &pkg.Testsets[i]
+		}
+	}
+	return &pkg.Testsets[0]
+}
+
+// MainSolution returns the solution which makes the answers, nil if there is none
+func (pkg *Package) MainSolution() *Solution {
+	for i := range pkg.Solutions {
+		if pkg.Solutions[i].Tag == MainSolutionTag {
+			return &pkg.Solutions[i]
+		}
+	}
+	return nil
+}
+
+// Executable returns the program named like the first word of a generation command, e.g. files/gen.cpp for "gen 10 1"
+func (pkg *Package) Executable(name string) *Source {
+	for i, executable := range pkg.Executables {
+		base := filepath.Base(executable.Path)
+		if strings.TrimSuffix(base, filepath.Ext(base)) == name {
+			return &pkg.Executables[i]
+		}
+	}
+	return nil
+}
+
+// InputPath returns the path of the input of the test numbered from 1, relative to the package
+func (testset *Testset) InputPath(index int) string {
+	return filepath.FromSlash(fmt.Sprintf(testset.InputPathPattern, index))
+}
+
+// AnswerPath returns the path of the answer of the test numbered from 1, relative to the package
+func (testset *Testset) AnswerPath(index int) string {
+	return filepath.FromSlash(fmt.Sprintf(testset.AnswerPathPattern, index))
+}
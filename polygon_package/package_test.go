@@ -0,0 +1,86 @@
+package polygon_package
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const descriptor = `<?xml version="1.0" encoding="utf-8" standalone="no"?>
+<problem revision="3" short-name="a-plus-b" url="https://polygon.codeforces.com/p/user/a-plus-b">
+    <names>
+        <name language="polish" value="A plus B"/>
+        <name language="english" value="A+B"/>
+    </names>
+    <judging cpu-name="Intel(R) Core(TM) i3-8100 CPU @ 3.60GHz" cpu-speed="3600" input-file="" output-file="">
+        <testset name="tests">
+            <time-limit>2000</time-limit>
+            <memory-limit>268435456</memory-limit>
+            <test-count>3</test-count>
+            <input-path-pattern>tests/%02d</input-path-pattern>
+            <answer-path-pattern>tests/%02d.a</answer-path-pattern>
+            <tests>
+                <test method="manual" sample="true"/>
+                <test cmd="gen 10 1" method="generated"/>
+                <test cmd="gen 1000 2" method="generated"/>
+            </tests>
+        </testset>
+    </judging>
+    <files>
+        <executables>
+            <executable>
+                <source path="files/gen.cpp" type="cpp.g++17"/>
+            </executable>
+        </executables>
+    </files>
+    <assets>
+        <checker name="std::ncmp.cpp" type="testlib">
+            <source path="files/check.cpp" type="cpp.g++17"/>
+        </checker>
+        <solutions>
+            <solution tag="wrong-answer">
+                <source path="solutions/wa.cpp" type="cpp.g++17"/>
+            </solution>
+            <solution tag="main">
+                <source path="solutions/sol.cpp" type="cpp.g++17"/>
+            </solution>
+        </solutions>
+    </assets>
+</problem>
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if pkg, err := Load(dir); pkg != nil || err != nil {
+		t.Errorf("Expect no package, but found %v %v.", pkg, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, DescriptorFileName), []byte(descriptor), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg.Name() != "A+B" {
+		t.Errorf("Expect A+B, but found %v.", pkg.Name())
+	}
+	testset := pkg.Testset()
+	if testset.TimeLimit != 2000 || testset.MemoryLimit != 268435456 || len(testset.Tests) != 3 {
+		t.Errorf("Expect 2000ms, 256MB and 3 tests, but found %v %v %v.", testset.TimeLimit, testset.MemoryLimit, len(testset.Tests))
+	}
+	if path := testset.AnswerPath(2); path != filepath.FromSlash("tests/02.a") {
+		t.Errorf("Expect tests/02.a, but found %v.", path)
+	}
+	if !testset.Tests[0].Sample || testset.Tests[1].Cmd != "gen 10 1" {
+		t.Errorf("Expect a sample and a generated test, but found %v.", testset.Tests)
+	}
+	if pkg.Checker.Path != "files/check.cpp" {
+		t.Errorf("Expect files/check.cpp, but found %v.", pkg.Checker.Path)
+	}
+	if main := pkg.MainSolution(); main == nil || main.Source.Path != "solutions/sol.cpp" {
+		t.Errorf("Expect solutions/sol.cpp, but found %v.", main)
+	}
+	if gen := pkg.Executable("gen"); gen == nil || gen.Path != "files/gen.cpp" {
+		t.Errorf("Expect files/gen.cpp, but found %v.", gen)
+	}
+}
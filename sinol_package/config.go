@@ -0,0 +1,120 @@
+package sinol_package
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/Arapak/sio-tool/util"
+	"gopkg.in/yaml.v2"
+)
+
+const ConfigFileName = "config.yml"
+
+// Config is the config.yml of a Sinol package. Time limits are in milliseconds, memory limits in kilobytes.
+// TimeLimits and MemoryLimits override the limits for a group (e.g. 1) or a single test (e.g. 1a).
+type Config struct {
+	Title        string          `yaml:"title,omitempty"`
+	TimeLimit    int             `yaml:"time_limit,omitempty"`
+	MemoryLimit  int             `yaml:"memory_limit,omitempty"`
+	TimeLimits   Limits          `yaml:"time_limits,omitempty"`
+	MemoryLimits Limits          `yaml:"memory_limits,omitempty"`
+	Scores       map[int]float64 `yaml:"scores,omitempty"`
+}
+
+// Limits are the limits of groups (e.g. 1) or tests (e.g. 1a)
+type Limits map[string]int
+
+// MarshalYAML saves the groups as numbers, like Sinol does
+func (limits Limits) MarshalYAML() (interface{}, error) {
+	keys := make([]string, 0, len(limits))
+	for key := range limits {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var slice yaml.MapSlice
+	for _, key := range keys {
+		var item interface{} = key
+		if group, err := strconv.Atoi(key); err == nil {
+			item = group
+		}
+		slice = append(slice, yaml.MapItem{Key: item, Value: limits[key]})
+	}
+	return slice, nil
+}
+
+func overriddenLimit(limits Limits, group int, letters string) (limit int, ok bool) {
+	if limit, ok = limits[fmt.Sprint(group, letters)]; ok {
+		return
+	}
+	limit, ok = limits[fmt.Sprint(group)]
+	return
+}
+
+// TestLimits returns the limits of the test, zero if the package doesn't give them
+func (config *Config) TestLimits(task, test string) (timeLimit, memoryLimit int) {
+	timeLimit, memoryLimit = config.TimeLimit, config.MemoryLimit
+	group, letters, ok := TestID(task, test)
+	if !ok {
+		return
+	}
+	if limit, ok := overriddenLimit(config.TimeLimits, group, letters); ok {
+		timeLimit = limit
+	}
+	if limit, ok := overriddenLimit(config.MemoryLimits, group, letters); ok {
+		memoryLimit = limit
+	}
+	return
+}
+
+// HasTestLimits is true if some group or test has its own limits
+func (config *Config) HasTestLimits() bool {
+	return len(config.TimeLimits) > 0 || len(config.MemoryLimits) > 0
+}
+
+var errConfigFound = errors.New("config found")
+
+// FindConfig returns the path of config.yml in the package (possibly in a subfolder), empty if there is none
+func FindConfig(packagePath string) (path string) {
+	if root := filepath.Join(packagePath, ConfigFileName); util.FileExists(root) {
+		return root
+	}
+	_ = filepath.Walk(packagePath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && info.Name() == ConfigFileName {
+			path = filePath
+			return errConfigFound
+		}
+		return nil
+	})
+	return
+}
+
+// LoadConfig reads config.yml of the package, a nil config means that the package doesn't have one
+func LoadConfig(packagePath string) (config *Config, err error) {
+	path := FindConfig(packagePath)
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	config = &Config{}
+	err = yaml.Unmarshal(data, config)
+	return
+}
+
+// SaveConfig writes config.yml into the root of the package
+func SaveConfig(packagePath string, config *Config) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(packagePath, ConfigFileName), data, 0644)
+}
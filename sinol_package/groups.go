@@ -0,0 +1,93 @@
+package sinol_package
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// group 0 holds the example tests, which are worth no points
+const ExampleGroup = 0
+
+var testNameRegex = regexp.MustCompile(`^(\d+)([a-z]*)$`)
+var fullTestNameRegex = regexp.MustCompile(`^([A-Za-z_]+?)(\d+)([a-z]*)$`)
+
+func testNameParts(task, path string) (match []string) {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if task != "" && strings.HasPrefix(name, task) {
+		if m := testNameRegex.FindStringSubmatch(strings.TrimPrefix(name, task)); m != nil {
+			return []string{name, task, m[1], m[2]}
+		}
+	}
+	return fullTestNameRegex.FindStringSubmatch(name)
+}
+
+// TestID extracts the group and the letters of the test, e.g. 1 and a from abc1a.in
+func TestID(task, path string) (group int, letters string, ok bool) {
+	match := testNameParts(task, path)
+	if match == nil {
+		return
+	}
+	group, err := strconv.Atoi(match[2])
+	return group, match[3], err == nil
+}
+
+// TestGroup extracts the group from a test name like abc1a.in, abc0.in or abc1ocen.in
+func TestGroup(task, path string) (group int, ok bool) {
+	group, letters, ok := TestID(task, path)
+	if ok && letters == "ocen" {
+		return ExampleGroup, true
+	}
+	return
+}
+
+// TaskID extracts the task of the package from a test name, e.g. abc from abc1a.in
+func TaskID(path string) string {
+	if match := testNameParts("", path); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// Scores returns the points of every group, splitting 100 points equally if config.yml doesn't specify them
+func Scores(config *Config, groups []int) map[int]float64 {
+	if config != nil && len(config.Scores) > 0 {
+		return config.Scores
+	}
+	var scored []int
+	for _, group := range groups {
+		if group != ExampleGroup {
+			scored = append(scored, group)
+		}
+	}
+	sort.Ints(scored)
+	scores := make(map[int]float64)
+	if len(scored) == 0 {
+		return scores
+	}
+	base := 100 / len(scored)
+	remainder := 100 % len(scored)
+	for i, group := range scored {
+		scores[group] = float64(base)
+		if i >= len(scored)-remainder {
+			scores[group]++
+		}
+	}
+	return scores
+}
+
+// TestScore is the part of the points (from 0 to 1) a test gets on Sio:
+// full points up to half of the time limit, then decreasing linearly to 0 at the time limit
+func TestScore(points, timeInSeconds, timeLimitInSeconds float64) float64 {
+	score := points / 100
+	if timeLimitInSeconds <= 0 || timeInSeconds <= timeLimitInSeconds/2 {
+		return score
+	}
+	if timeInSeconds >= timeLimitInSeconds {
+		return 0
+	}
+	return score * (timeLimitInSeconds - timeInSeconds) / (timeLimitInSeconds / 2)
+}
@@ -0,0 +1,60 @@
+package sinol_package
+
+import "testing"
+
+func TestTestGroup(t *testing.T) {
+	tests := map[string]int{
+		"in/abc0.in":     0,
+		"abc1a.in":       1,
+		"abc12b.in":      12,
+		"abc1ocen.in":    0,
+		"out/kol21a.out": 1,
+	}
+	for path, expect := range tests {
+		task := "abc"
+		if path == "out/kol21a.out" {
+			task = "kol2"
+		}
+		group, ok := TestGroup(task, path)
+		if !ok || group != expect {
+			t.Errorf("Expect %v for %s, but found %v.", expect, path, group)
+		}
+	}
+	if _, ok := TestGroup("abc", "in/test.in"); ok {
+		t.Errorf("Expect test.in not to have a group.")
+	}
+}
+
+func TestScores(t *testing.T) {
+	scores := Scores(nil, []int{0, 1, 2, 3})
+	if scores[0] != 0 || scores[1] != 33 || scores[2] != 33 || scores[3] != 34 {
+		t.Errorf("Expect 0, 33, 33, 34, but found %v.", scores)
+	}
+	if score := TestScore(100, 0.75, 1); score != 0.5 {
+		t.Errorf("Expect 0.5, but found %v.", score)
+	}
+}
+
+func TestTestLimits(t *testing.T) {
+	config := &Config{
+		TimeLimit:    1000,
+		MemoryLimit:  65536,
+		TimeLimits:   map[string]int{"2": 3000, "2b": 5000},
+		MemoryLimits: map[string]int{"3": 131072},
+	}
+	tests := map[string][2]int{
+		"in/abc1a.in": {1000, 65536},
+		"in/abc2a.in": {3000, 65536},
+		"in/abc2b.in": {5000, 65536},
+		"in/abc3c.in": {1000, 131072},
+	}
+	for path, expect := range tests {
+		timeLimit, memoryLimit := config.TestLimits("abc", path)
+		if timeLimit != expect[0] || memoryLimit != expect[1] {
+			t.Errorf("Expect %v for %s, but found %v %v.", expect, path, timeLimit, memoryLimit)
+		}
+	}
+	if task := TaskID("in/kol21a.in"); task != "kol" {
+		t.Errorf("Expect kol, but found %v.", task)
+	}
+}
@@ -0,0 +1,136 @@
+package sinol_package
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Spec describes the tests made by st make-tests, similarly to Sinol's ingen.
+// The programs are given before the groups, every other line holds the arguments
+// of the generator for one test of the last group:
+//
+//	gen: abc-gen.cpp
+//	solution: abc.cpp
+//	validator: abc-val.cpp
+//	group 0
+//	5 1
+//	group 1 30
+//	100 1
+//	100 2
+type Spec struct {
+	Generator string
+	Solution  string
+	Validator string
+	Groups    []SpecGroup
+}
+
+// SpecGroup is a subtask, Points is negative if the spec doesn't give them
+type SpecGroup struct {
+	Group  int
+	Points float64
+	Args   []string
+}
+
+type SpecTest struct {
+	Name  string
+	Group int
+	Args  string
+}
+
+func ParseSpec(data []byte) (spec *Spec, err error) {
+	spec = &Spec{}
+	groups := make(map[int]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if key, value, ok := strings.Cut(line, ":"); ok && len(spec.Groups) == 0 {
+			value = strings.TrimSpace(value)
+			switch strings.TrimSpace(key) {
+			case "gen":
+				spec.Generator = value
+			case "solution":
+				spec.Solution = value
+			case "validator":
+				spec.Validator = value
+			default:
+				return nil, fmt.Errorf("line %v: unknown key %v", lineNumber, key)
+			}
+			continue
+		}
+		if fields[0] == "group" {
+			if len(fields) < 2 || len(fields) > 3 {
+				return nil, fmt.Errorf("line %v: expected \"group <number> [points]\"", lineNumber)
+			}
+			group := SpecGroup{Points: -1}
+			if group.Group, err = strconv.Atoi(fields[1]); err != nil || group.Group < 0 {
+				return nil, fmt.Errorf("line %v: invalid group %v", lineNumber, fields[1])
+			}
+			if groups[group.Group] {
+				return nil, fmt.Errorf("line %v: group %v is given twice", lineNumber, group.Group)
+			}
+			groups[group.Group] = true
+			if len(fields) == 3 {
+				if group.Points, err = strconv.ParseFloat(fields[2], 64); err != nil || group.Points < 0 {
+					return nil, fmt.Errorf("line %v: invalid points %v", lineNumber, fields[2])
+				}
+			}
+			spec.Groups = append(spec.Groups, group)
+			continue
+		}
+		if len(spec.Groups) == 0 {
+			return nil, fmt.Errorf("line %v: the test is not in any group", lineNumber)
+		}
+		last := &spec.Groups[len(spec.Groups)-1]
+		last.Args = append(last.Args, strings.Join(fields, " "))
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(spec.Groups) == 0 {
+		return nil, fmt.Errorf("the spec has no tests")
+	}
+	return spec, nil
+}
+
+// testLetters numbers the tests of a group like Sinol: a, b, ..., z, aa, ab, ...
+func testLetters(index int) (letters string) {
+	for index++; index > 0; index = (index - 1) / 26 {
+		letters = string(rune('a'+(index-1)%26)) + letters
+	}
+	return
+}
+
+// TestName names the test of the group numbered from 0 in the Sinol way, e.g. abc1a, abc1b, abc2a
+func TestName(task string, group, index int) string {
+	return fmt.Sprintf("%v%v%v", task, group, testLetters(index))
+}
+
+func (spec *Spec) Tests(task string) (tests []SpecTest) {
+	for _, group := range spec.Groups {
+		for i, args := range group.Args {
+			tests = append(tests, SpecTest{TestName(task, group.Group, i), group.Group, args})
+		}
+	}
+	return
+}
+
+// Scores returns the points of the groups, nil if the spec doesn't give them
+func (spec *Spec) Scores() map[int]float64 {
+	var scores map[int]float64
+	for _, group := range spec.Groups {
+		if group.Points >= 0 {
+			if scores == nil {
+				scores = make(map[int]float64)
+			}
+			scores[group.Group] = group.Points
+		}
+	}
+	return scores
+}
@@ -0,0 +1,39 @@
+package sinol_package
+
+import "testing"
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec([]byte(`# tests of abc
+gen: abc-gen.cpp
+group 0
+5 1
+group 1 30
+100 1
+100  2
+`))
+	if err != nil {
+		t.Fatalf("Expect no error, but found %v.", err)
+	}
+	if spec.Generator != "abc-gen.cpp" || spec.Solution != "" {
+		t.Errorf("Expect abc-gen.cpp and no solution, but found %v and %v.", spec.Generator, spec.Solution)
+	}
+	tests := spec.Tests("abc")
+	expect := []SpecTest{{"abc0a", 0, "5 1"}, {"abc1a", 1, "100 1"}, {"abc1b", 1, "100 2"}}
+	if len(tests) != len(expect) {
+		t.Fatalf("Expect %v, but found %v.", expect, tests)
+	}
+	for i := range expect {
+		if tests[i] != expect[i] {
+			t.Errorf("Expect %v, but found %v.", expect[i], tests[i])
+		}
+	}
+	if scores := spec.Scores(); len(scores) != 1 || scores[1] != 30 {
+		t.Errorf("Expect only group 1 with 30 points, but found %v.", scores)
+	}
+	if letters := testLetters(26); letters != "aa" {
+		t.Errorf("Expect aa, but found %v.", letters)
+	}
+	if _, err := ParseSpec([]byte("1 2\n")); err == nil {
+		t.Errorf("Expect an error for a test outside of a group.")
+	}
+}
@@ -0,0 +1,104 @@
+package sinol_package
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Arapak/sio-tool/util"
+)
+
+// the task id can't have digits, they would be taken for the group of the test
+var taskIDRegex = regexp.MustCompile(`^[a-z]+$`)
+
+func testNames(dir, ext string) (names map[string]bool, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	names = make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ext {
+			names[strings.TrimSuffix(entry.Name(), ext)] = true
+		}
+	}
+	return
+}
+
+// Validate checks the structure of the package, whose folder is named after the task.
+// It returns the problems found, none means that the package is valid.
+func Validate(packagePath string) (problems []string) {
+	task := filepath.Base(packagePath)
+	if !taskIDRegex.MatchString(task) {
+		problems = append(problems, fmt.Sprintf("the task id %v should consist of lowercase letters", task))
+	}
+
+	var config *Config
+	var err error
+	if !util.FileExists(filepath.Join(packagePath, ConfigFileName)) {
+		problems = append(problems, fmt.Sprintf("there is no %v in the package", ConfigFileName))
+	} else if config, err = LoadConfig(packagePath); err != nil {
+		problems = append(problems, fmt.Sprintf("%v: %v", ConfigFileName, err.Error()))
+		config = nil
+	} else {
+		if config.TimeLimit <= 0 {
+			problems = append(problems, fmt.Sprintf("%v has no time_limit", ConfigFileName))
+		}
+		if config.MemoryLimit <= 0 {
+			problems = append(problems, fmt.Sprintf("%v has no memory_limit", ConfigFileName))
+		}
+	}
+
+	if models, _ := filepath.Glob(filepath.Join(packagePath, "prog", task+".*")); len(models) == 0 {
+		problems = append(problems, fmt.Sprintf("there is no model solution prog/%v.*", task))
+	}
+
+	inputs, err := testNames(filepath.Join(packagePath, "in"), ".in")
+	if err != nil || len(inputs) == 0 {
+		return append(problems, "there are no tests in in/")
+	}
+	outputs, err := testNames(filepath.Join(packagePath, "out"), ".out")
+	if err != nil {
+		outputs = make(map[string]bool)
+	}
+	groups := make(map[int]bool)
+	for name := range inputs {
+		if !strings.HasPrefix(name, task) || !testNameRegex.MatchString(strings.TrimPrefix(name, task)) {
+			problems = append(problems, fmt.Sprintf("in/%v.in is not named like %v<group><letters>.in", name, task))
+		} else if group, ok := TestGroup(task, name); ok {
+			groups[group] = true
+		}
+		if !outputs[name] {
+			problems = append(problems, fmt.Sprintf("in/%v.in has no output", name))
+		}
+	}
+	for name := range outputs {
+		if !inputs[name] {
+			problems = append(problems, fmt.Sprintf("out/%v.out has no input", name))
+		}
+	}
+
+	if config != nil && len(config.Scores) > 0 {
+		sum := 0.0
+		for group := range groups {
+			if _, ok := config.Scores[group]; !ok && group != ExampleGroup {
+				problems = append(problems, fmt.Sprintf("group %v has no score in %v", group, ConfigFileName))
+			}
+		}
+		for group, score := range config.Scores {
+			if !groups[group] {
+				problems = append(problems, fmt.Sprintf("group %v has a score in %v, but no tests", group, ConfigFileName))
+			}
+			sum += score
+		}
+		if math.Abs(sum-100) > 1e-9 {
+			problems = append(problems, fmt.Sprintf("the scores in %v sum up to %v instead of 100", ConfigFileName, sum))
+		}
+	}
+	sort.Strings(problems)
+	return
+}
@@ -0,0 +1,49 @@
+package sinol_package
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	packagePath := filepath.Join(t.TempDir(), "abc")
+	writeFiles(t, packagePath, map[string]string{
+		"config.yml":   "time_limit: 1000\nmemory_limit: 65536\nscores:\n  1: 40\n  2: 60\n",
+		"prog/abc.cpp": "",
+		"in/abc0.in":   "", "out/abc0.out": "",
+		"in/abc1a.in": "", "out/abc1a.out": "",
+		"in/abc2a.in": "", "out/abc2a.out": "",
+	})
+	if problems := Validate(packagePath); len(problems) != 0 {
+		t.Errorf("Expect a valid package, but found %v.", problems)
+	}
+
+	writeFiles(t, packagePath, map[string]string{
+		"config.yml":  "time_limit: 1000\nscores:\n  1: 40\n  2: 50\n",
+		"in/test1.in": "", "out/test1.out": "",
+		"out/abc2b.out": "",
+	})
+	expect := []string{
+		"config.yml has no memory_limit",
+		"in/test1.in is not named like abc<group><letters>.in",
+		"out/abc2b.out has no input",
+		"the scores in config.yml sum up to 90 instead of 100",
+	}
+	if problems := Validate(packagePath); !reflect.DeepEqual(problems, expect) {
+		t.Errorf("Expect %v, but found %v.", expect, problems)
+	}
+}
@@ -24,7 +24,7 @@ type Info struct {
 	ProblemAlias string `json:"problem_alias"`
 	Round        string `json:"round"`
 	SubmissionID string `json:"submission_id"`
-	RootPath     string
+	RootPath     string `json:"-"`
 }
 
 const ErrorNeedProblemAlias = "you have to specify the Problem alias"
@@ -14,6 +14,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/Arapak/sio-tool/database_client"
+	"github.com/Arapak/sio-tool/judge"
 	"github.com/Arapak/sio-tool/sio_samples"
 	"github.com/Arapak/sio-tool/util"
 	"github.com/PuerkitoBio/goquery"
@@ -27,7 +28,7 @@ const ErrorUnrecognizedStatementFormat = `unrecognized statement format`
 
 const StandardIOReg = `(\nKomunikacja\n|\nOpis interfejsu\s+)`
 
-func parseSiteStatement(body []byte) (name string, standardIO bool, input [][]byte, output [][]byte, err error) {
+func parseSiteStatement(body []byte) (name string, standardIO bool, limits judge.Limits, input [][]byte, output [][]byte, err error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
 	if err != nil {
 		return
@@ -35,6 +36,7 @@ func parseSiteStatement(body []byte) (name string, standardIO bool, input [][]by
 	name = doc.Find("h1").First().Text()
 	reg := regexp.MustCompile(StandardIOReg)
 	standardIO = !reg.Match(body)
+	limits = sio_samples.FindLimits([]byte(doc.Text()))
 	statement := doc.Find("section.main-content").First().Text()
 	if standardIO {
 		input, output, err = sio_samples.FindSamples([]byte(statement), body)
@@ -72,13 +74,14 @@ func getNameFromPdf(statement []byte) (problemName string) {
 	return getNameFromOiPdf(statement)
 }
 
-func parsePdf(body []byte) (name string, standardIO bool, input [][]byte, output [][]byte, err error) {
+func parsePdf(body []byte) (name string, standardIO bool, limits judge.Limits, input [][]byte, output [][]byte, err error) {
 	statement, err := util.PdfToTextRaw(body)
 	if err != nil {
 		return
 	}
 	reg := regexp.MustCompile(StandardIOReg)
 	standardIO = !reg.Match(statement)
+	limits = sio_samples.FindLimits(statement)
 	name = getNameFromPdf(statement)
 	if name == "" {
 		err = errors.New("parsing problem failed")
@@ -90,7 +93,7 @@ func parsePdf(body []byte) (name string, standardIO bool, input [][]byte, output
 	return
 }
 
-func (c *SioClient) ParseProblem(host, contestID, problemAlias, path string, mu *sync.Mutex) (name string, samples int, standardIO bool, perf util.Performance, err error) {
+func (c *SioClient) ParseProblem(host, contestID, problemAlias, path string, mu *sync.Mutex) (name string, samples int, standardIO bool, limits judge.Limits, perf util.Performance, err error) {
 	perf.StartFetching()
 
 	resp, err := c.client.Get(ProblemURL(host, contestID, problemAlias))
@@ -110,9 +113,9 @@ func (c *SioClient) ParseProblem(host, contestID, problemAlias, path string, mu
 			return
 		}
 		if resp.Header.Get("Content-Type") == "application/pdf" {
-			name, standardIO, input, output, err = parsePdf(body)
+			name, standardIO, limits, input, output, err = parsePdf(body)
 		} else if strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
-			name, standardIO, input, output, err = parseSiteStatement(body)
+			name, standardIO, limits, input, output, err = parseSiteStatement(body)
 		} else {
 			err = errors.New(ErrorUnrecognizedStatementFormat)
 		}
@@ -155,8 +158,8 @@ func (c *SioClient) ParseProblem(host, contestID, problemAlias, path string, mu
 	return
 }
 
-func (c *SioClient) parse(contestID, problemAlias, path string, mu *sync.Mutex) (perf util.Performance, err error) {
-	name, samples, standardIO, perf, err := c.ParseProblem(c.host, contestID, problemAlias, path, mu)
+func (c *SioClient) parse(contestID, problemAlias, path string, mu *sync.Mutex) (standardIO bool, limits judge.Limits, perf util.Performance, err error) {
+	name, samples, standardIO, limits, perf, err := c.ParseProblem(c.host, contestID, problemAlias, path, mu)
 
 	warns := ""
 	if !standardIO {
@@ -172,7 +175,7 @@ func (c *SioClient) parse(contestID, problemAlias, path string, mu *sync.Mutex)
 	if err != nil {
 		color.Red("Failed (%v). Error: %v", problemAlias, err.Error())
 	} else {
-		_, _ = ansi.Printf("%v %v\n", color.GreenString("Parsed %v (%v) with %v samples.", name, problemAlias, samples), warns)
+		_, _ = ansi.Printf("%v %v\n", color.GreenString("Parsed %v (%v) with %v samples (%v).", name, problemAlias, samples, limits), warns)
 	}
 	if mu != nil {
 		mu.Unlock()
@@ -180,7 +183,17 @@ func (c *SioClient) parse(contestID, problemAlias, path string, mu *sync.Mutex)
 	return
 }
 
-func (c *SioClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, paths []string, err error) {
+// ParsedProblem describes a successfully parsed problem
+type ParsedProblem struct {
+	Info       Info
+	Name       string
+	Link       string
+	Path       string
+	StandardIO bool
+	Limits     judge.Limits
+}
+
+func (c *SioClient) Parse(info Info, db *sql.DB) (parsed []ParsedProblem, paths []string, err error) {
 	start := time.Now()
 
 	color.Cyan("Parse " + info.Hint())
@@ -248,8 +261,6 @@ func (c *SioClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, paths [
 
 	var avgPerformance util.Performance
 
-	parsed := 0
-
 	const numberOfWorkers = 50
 	index := 0
 
@@ -279,7 +290,9 @@ func (c *SioClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, paths [
 				}
 
 				var perf util.Performance
-				perf, err = c.parse(contestID, problemAlias, path, &mu)
+				var standardIO bool
+				var limits judge.Limits
+				standardIO, limits, perf, err = c.parse(contestID, problemAlias, path, &mu)
 				mu.Lock()
 				avgPerformance.Fetching += perf.Fetching
 				avgPerformance.Parsing += perf.Parsing
@@ -296,19 +309,26 @@ func (c *SioClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, paths [
 					if err != nil {
 						color.Red(err.Error())
 					}
-					parsed++
+					parsed = append(parsed, ParsedProblem{
+						Info:       Info{Contest: info.Contest, ProblemAlias: problemAlias, Round: problems[problemIndex].Round},
+						Name:       task.Name,
+						Link:       task.Link,
+						Path:       path,
+						StandardIO: standardIO,
+						Limits:     limits,
+					})
 				}
 				mu.Unlock()
 			}
 		}(i)
 	}
 	wg.Wait()
-	color.Green("Successfully parsed %v problems.\n", parsed)
+	color.Green("Successfully parsed %v problems.\n", len(parsed))
 	avgPerformance.Fetching = util.AverageTime(avgPerformance.Fetching, len(problems))
 	avgPerformance.Parsing = util.AverageTime(avgPerformance.Parsing, len(problems))
 	fmt.Printf("Average: (%v)\n", avgPerformance.Parse())
 	fmt.Printf("Total: %s\n", time.Since(start).Round(time.Millisecond))
-	if parsed != len(problems) {
+	if len(parsed) != len(problems) {
 		err = errors.New(ErrorParsingProblemsFailed)
 	}
 	return
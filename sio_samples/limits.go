@@ -0,0 +1,48 @@
+package sio_samples
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Arapak/sio-tool/judge"
+)
+
+// the limits are stated like "Dostępna pamięć: 256 MB" in Sinol and OI statements,
+// "Limit czasu: 1 s" or "Limit pamięci: 64 MiB" in the header tables and the HTML statements
+const TimeLimitReg = `(?i)(?:limit czasu|time limit)(?: per test)?[\s:|]*(?P<value>\d+(?:[.,]\d+)?)\s*(?P<unit>ms|s|sek|sekund[ay]?|seconds?)\b`
+const MemoryLimitReg = `(?i)(?:dostępna pamięć|limit pamięci|memory limit)[\s:|]*(?P<value>\d+(?:[.,]\d+)?)\s*(?P<unit>[kmg]i?b|megabytes?)\b`
+
+func findLimit(reg *regexp.Regexp, statement []byte) (value float64, unit string) {
+	match := reg.FindSubmatch(statement)
+	if match == nil {
+		return
+	}
+	for i, name := range reg.SubexpNames() {
+		if name == "value" {
+			value, _ = strconv.ParseFloat(strings.ReplaceAll(string(match[i]), ",", "."), 64)
+		} else if name == "unit" {
+			unit = strings.ToLower(string(match[i]))
+		}
+	}
+	return
+}
+
+// FindLimits finds the time and the memory limit in the statement, the ones not found are zero
+func FindLimits(statement []byte) (limits judge.Limits) {
+	value, unit := findLimit(regexp.MustCompile(TimeLimitReg), statement)
+	limits.TimeLimitInSeconds = value
+	if unit == "ms" {
+		limits.TimeLimitInSeconds = value / 1000
+	}
+	value, unit = findLimit(regexp.MustCompile(MemoryLimitReg), statement)
+	switch strings.TrimSuffix(strings.TrimSuffix(unit, "b"), "i") {
+	case "k":
+		limits.MemoryLimitInMegabytes = value / 1024
+	case "g":
+		limits.MemoryLimitInMegabytes = value * 1024
+	default:
+		limits.MemoryLimitInMegabytes = value
+	}
+	return
+}
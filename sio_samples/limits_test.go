@@ -0,0 +1,22 @@
+package sio_samples
+
+import (
+	"testing"
+
+	"github.com/Arapak/sio-tool/judge"
+)
+
+func TestFindLimits(t *testing.T) {
+	statements := map[string]judge.Limits{
+		"Zadanie: KOL\nKolorowy wąż\nEtap I. Plik źródłowy kol.* Dostępna pamięć: 256 MB.\n": {MemoryLimitInMegabytes: 256},
+		"Limit czasu: 1,5 s\nLimit pamięci: 64 MiB\n":                                        {TimeLimitInSeconds: 1.5, MemoryLimitInMegabytes: 64},
+		"Limit czasu | 500 ms | Limit pamięci | 1 GB":                                        {TimeLimitInSeconds: 0.5, MemoryLimitInMegabytes: 1024},
+		"Time limit: 2 seconds\nMemory limit: 32768 KB\n":                                    {TimeLimitInSeconds: 2, MemoryLimitInMegabytes: 32},
+		"Opis zadania bez limitów\n":                                                         {},
+	}
+	for statement, expected := range statements {
+		if limits := FindLimits([]byte(statement)); limits != expected {
+			t.Errorf("Expect %v, but found %v for %q.", expected, limits, statement)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Arapak/sio-tool/cmd"
 	"github.com/Arapak/sio-tool/codeforces_client"
 	"github.com/Arapak/sio-tool/config"
+	"github.com/Arapak/sio-tool/judge"
 	"github.com/Arapak/sio-tool/sio_client"
 	"github.com/Arapak/sio-tool/szkopul_client"
 	"github.com/Arapak/sio-tool/util"
@@ -41,21 +42,24 @@ Usage:
   st list [<specifier>...]
   st parse [<specifier>...]
   st gen [<alias>]
-  st test [--oiejq] [--memory_limit <memory_limit>] [--time_limit <time_limit>] [<file>]
-  st package_test [--oiejq] [--verbose] [--memory_limit <memory_limit>] [--time_limit <time_limit>] [<file>]
+  st test [--oiejq] [--cgroup] [--isolate] [--profile <profile>] [--stderr] [--memory_limit <memory_limit>] [--time_limit <time_limit>] [--checker <checker>] [--interactor <interactor>] [--compare <comparator>] [--side-by-side] [--report <format> [--report_file <report_file>]] [<file>]
+  st package_test [--oiejq] [--cgroup] [--isolate] [--verbose] [--profile <profile>] [--debug_failing] [--stderr] [--memory_limit <memory_limit>] [--time_limit <time_limit>] [--checker <checker>] [--interactor <interactor>] [--compare <comparator>] [--side-by-side] [--report <format> [--report_file <report_file>]] [--all] [--official] [<file> [<solutions>...]]
+  st make-tests [--workers <workers>] [<file>]
   st add_package <file>
   st download_packages [<specifier>...]
   st upload_package <file> [<specifier>...]
+  st export-package [--upload] [<file>]
   st watch [all] [<specifier>...]
   st open [<specifier>...]
   st stand [<specifier>...]
   st sid [<specifier>...]
   st race [<specifier>...]
   st pull [ac] [<specifier>...]
-  st stress-test [--oiejq] [--memory_limit <memory_limit>] [--time_limit <time_limit>] <specifier> [-s <solve>] [-b <brute>] [-g <generator>]
+  st stress-test [--oiejq] [--cgroup] [--isolate] [--profile <profile>] [--debug_failing] [--stderr] [--seed-start <seed>] [--iterations <iterations>] [--duration <duration>] [--workers <workers>] [--seed-arg] [--no-shrink] [--maximize <resource> [--top <top>] [--stop-on-limit]] [--memory_limit <memory_limit>] [--time_limit <time_limit>] <specifier> [-s <solve>] [-b <brute>] [-g <generator>] [--checker <checker>] [--validator <validator>] [--compare <comparator>] [--side-by-side]
   st db add [--source <source>] [-n <name>] [-p <path>] [-l <link>] [-c <contest>] [--shortname <shortname>] [--stage <stage>]
   st db find [--source <source>] [-n <name>] [-p <path>] [-l <link>] [-c <contest>] [--shortname <shortname>] [--stage <stage>]
   st db goto [--source <source>] [-n <name>] [-p <path>] [-l <link>] [-c <contest>] [--shortname <shortname>] [--stage <stage>]
+  st history [diff [<run> <other_run>]]
   st upgrade
 
 Options:
@@ -67,6 +71,44 @@ Options:
   					   Path to the brute force solution file
   -g <generator>, --generator <generator>, <generator>
   					   Path to the test generator file
+  --checker <checker>  Path to the checker file, run as "checker in out ans"
+  --compare <comparator>
+                       How to compare the output with the answer: lines (default),
+                       exact, tokens, tokens-ignore-case, float[:epsilon] or
+                       unordered-lines
+  --report <format>    Save the results of all tests in the given format (json or
+                       junit)
+  --report_file <report_file>
+                       Path of the report (default is report.json or report.xml)
+  --side-by-side       Show the output and the answer side by side when they differ
+  --all                Compare all solutions of the problem in the current folder
+  --official           Compare the solutions with the official ones from the package
+  --profile <profile>  Build the solution with the given profile of its template
+                       (e.g. debug), the default is release
+  --debug_failing      Re-run the first failing test with the solution built with
+                       the debug profile and show its stderr
+  --stderr             Show what the solution printed to stderr also for the passed
+                       tests (it is always shown for the failed ones)
+  --seed-start <seed>  The seed of the first stress test (default is 1)
+  --iterations <iterations>
+                       Stop the stress test after this many tests
+  --duration <duration>
+                       Stop the stress test after this time, e.g. 90, 30s or 10m
+  --workers <workers>  Number of tests run at the same time (default is 10)
+  --seed-arg           Pass the seed to the generator as its argument instead of
+                       on the standard input
+  --no-shrink          Don't look for a smaller test when the stress test fails
+  --maximize <resource>
+                       Look for the tests on which the solution uses the most time
+                       or memory instead of the failing ones
+  --top <top>          Number of the worst tests kept with --maximize (default is 5)
+  --stop-on-limit      Stop --maximize when a test exceeds the time or memory limit
+  --upload             Upload the exported package to sio, like upload_package
+  --validator <validator>
+                       Path to the validator file, run with the test on the standard
+                       input, which exits with a non-zero code for invalid tests
+  --interactor <interactor>
+                       Path to the interactor file, run as "interactor in out ans"
   -f <file>, --file <file>, <file>
                        Path to the file. E.g. "a.cpp", "./temp/a.cpp"
   --source <source>, <source> 
@@ -94,11 +136,13 @@ Options:
   <alias>              Template's alias, e.g., "cpp"
   ac                   The status of the submission is Accepted.
   -o, --oiejq          Use oiejq for running tests
+  --cgroup             Run every test in a separate cgroup v2 instead of oiejq
+  --isolate            Run tests in new mount, network and ipc namespaces (with --cgroup)
   -v, --verbose        Print verdict of every test
   -m <memory_limit>, --memory_limit <memory_limit>, <memory_limit>
-             Set oiejq's memory limit in MiB (default is 1024 (1 GiB))
+             Set the memory limit in MiB (default is 1024 (1 GiB))
   -t <time_limit>, --time_limit <time_limit>, <time_limit>  
-             Set oiejq's time limit in seconds (default is 10s)
+             Set the time limit in seconds (default is 10s)
 
 Examples:
   st config            Configure the sio-tool.
@@ -130,6 +174,12 @@ Examples:
   st add_package ~/tests
                        Add package (set of tests) for a task you are currently in 
   st test_package      Test your solution on a package added before
+  st package_test --all
+                       Run all solutions of the problem on the package and compare
+                       their verdicts and times
+  st export-package --upload
+                       Make a Sinol package of the problem in the current folder
+                       and upload it to sio
   st watch             Watch the first 10 submissions for the current contest.
   st watch all         Watch all submissions for the current contest.
   st open 1136a        Use your default web browser to open the page for the contest.
@@ -157,6 +207,8 @@ Examples:
 					   Find all problems in the database that contain the string "square" (ignoring capitalization).
   st db goto -n "square" -c 100
 					   Returns the path of the task with a name that contains "square" and has contest id 100 (if you configure your shell correctly, it can automatically cd into the path (example of .bashrc in CONFIG.md))
+  st history           List the test and package_test runs of the problem in the current folder.
+  st history diff 3 7  Show the tests whose verdict or time changed between runs 3 and 7 (by default the last two runs of the same kind).
   st upgrade           Upgrade the "st" to the latest version from GitHub.
 
 
@@ -164,6 +216,7 @@ Files:
   st will save some data in some files:
 
   "~/.st/config"        Configuration file, including templates, etc.
+  "~/.st/cache"         Cache of compiled programs
   "~/.st/codeforces_session"    Codeforces session file, including cookies, handle, password, etc.
   "~/.st/szkopul_session"       Szkopul session file, including username and password
   "~/.st/sio_session"           Sio session file, including username and password
@@ -222,6 +275,7 @@ Script in template:
 	sio_client.Init(sioTalentClnPath, config.Instance.SioTalentHost, config.Instance.Proxy, sio_client.Talent)
 
 	err := cmd.Eval(opts)
+	judge.ReleaseCgroup()
 	if err != nil {
 		fmt.Println(util.RedString(err.Error()))
 		os.Exit(1)
@@ -28,7 +28,7 @@ type Info struct {
 	ProblemAlias string `json:"problem_alias"`
 	ProblemID    string `json:"problem_id"`
 	SubmissionID string `json:"submission_id"`
-	RootPath     string
+	RootPath     string `json:"-"`
 }
 
 const ErrorNeedContestID = "you have to specify the Contest ID"
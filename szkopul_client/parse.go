@@ -14,6 +14,7 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/Arapak/sio-tool/database_client"
+	"github.com/Arapak/sio-tool/judge"
 	"github.com/Arapak/sio-tool/sio_samples"
 	"github.com/Arapak/sio-tool/util"
 	"github.com/PuerkitoBio/goquery"
@@ -30,7 +31,7 @@ const PdfStatementProblemURL = `/problemset/problem/%v/statement`
 
 const StandardIOReg = `(\nKomunikacja\n|\nOpis interfejsu\s+)`
 
-func parseSiteStatement(body []byte) (name string, alias string, standardIO bool, input [][]byte, output [][]byte, err error) {
+func parseSiteStatement(body []byte) (name string, alias string, standardIO bool, limits judge.Limits, input [][]byte, output [][]byte, err error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
 	if err != nil {
 		return
@@ -38,6 +39,7 @@ func parseSiteStatement(body []byte) (name string, alias string, standardIO bool
 	alias, name = GetAliasAndName(doc.Find("h1").First().Text())
 	reg := regexp.MustCompile(StandardIOReg)
 	standardIO = !reg.Match(body)
+	limits = sio_samples.FindLimits([]byte(doc.Text()))
 	statement := doc.Find(".nav-content").First().Text()
 	if standardIO {
 		input, output, err = sio_samples.FindSamples([]byte(statement), body)
@@ -58,13 +60,14 @@ func getNamesFromPdf(statement []byte) (problemName string, alias string) {
 	return
 }
 
-func parsePdf(body []byte) (name string, alias string, standardIO bool, input [][]byte, output [][]byte, err error) {
+func parsePdf(body []byte) (name string, alias string, standardIO bool, limits judge.Limits, input [][]byte, output [][]byte, err error) {
 	statement, err := util.PdfToTextRaw(body)
 	if err != nil {
 		return
 	}
 	reg := regexp.MustCompile(StandardIOReg)
 	standardIO = !reg.Match(statement)
+	limits = sio_samples.FindLimits(statement)
 	name, alias = getNamesFromPdf(statement)
 	if name == "" || alias == "" {
 		err = errors.New("parsing problem failed")
@@ -76,7 +79,7 @@ func parsePdf(body []byte) (name string, alias string, standardIO bool, input []
 	return
 }
 
-func (c *SzkopulClient) ParseProblem(host, problemID, path string, mu *sync.Mutex) (name string, alias string, samples int, standardIO bool, perf util.Performance, err error) {
+func (c *SzkopulClient) ParseProblem(host, problemID, path string, mu *sync.Mutex) (name string, alias string, samples int, standardIO bool, limits judge.Limits, perf util.Performance, err error) {
 	perf.StartFetching()
 
 	resp, err := c.client.Get(fmt.Sprintf(host+PdfStatementProblemURL, problemID))
@@ -95,7 +98,7 @@ func (c *SzkopulClient) ParseProblem(host, problemID, path string, mu *sync.Mute
 		if err != nil {
 			return
 		}
-		name, alias, standardIO, input, output, err = parsePdf(body)
+		name, alias, standardIO, limits, input, output, err = parsePdf(body)
 	} else if resp.StatusCode == 403 {
 		resp, err = c.client.Get(fmt.Sprintf(host+SiteStatementProblemURL, problemID))
 		if err != nil {
@@ -111,7 +114,7 @@ func (c *SzkopulClient) ParseProblem(host, problemID, path string, mu *sync.Mute
 		defer resp.Body.Close()
 		body, err = io.ReadAll(resp.Body)
 		if err == nil {
-			name, alias, standardIO, input, output, err = parseSiteStatement(body)
+			name, alias, standardIO, limits, input, output, err = parseSiteStatement(body)
 		}
 	} else if resp.StatusCode == 503 {
 		err = errors.New(ErrorServiceUnavailable)
@@ -154,8 +157,8 @@ func (c *SzkopulClient) ParseProblem(host, problemID, path string, mu *sync.Mute
 	return
 }
 
-func (c *SzkopulClient) parse(problemID, path string, mu *sync.Mutex) (perf util.Performance, err error) {
-	name, alias, samples, standardIO, perf, err := c.ParseProblem(c.host, problemID, path, mu)
+func (c *SzkopulClient) parse(problemID, path string, mu *sync.Mutex) (standardIO bool, limits judge.Limits, perf util.Performance, err error) {
+	name, alias, samples, standardIO, limits, perf, err := c.ParseProblem(c.host, problemID, path, mu)
 
 	warns := ""
 	if !standardIO {
@@ -171,7 +174,7 @@ func (c *SzkopulClient) parse(problemID, path string, mu *sync.Mutex) (perf util
 	if err != nil {
 		color.Red("Failed (%v). Error: %v", problemID, err.Error())
 	} else {
-		_, _ = ansi.Printf("%v %v\n", color.GreenString("Parsed %v (%v) with %v samples.", name, alias, samples), warns)
+		_, _ = ansi.Printf("%v %v\n", color.GreenString("Parsed %v (%v) with %v samples (%v).", name, alias, samples, limits), warns)
 	}
 	if mu != nil {
 		mu.Unlock()
@@ -179,7 +182,28 @@ func (c *SzkopulClient) parse(problemID, path string, mu *sync.Mutex) (perf util
 	return
 }
 
-func (c *SzkopulClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, paths []string, err error) {
+// ParsedProblem describes a successfully parsed problem
+type ParsedProblem struct {
+	Info       Info
+	Name       string
+	Link       string
+	Path       string
+	StandardIO bool
+	Limits     judge.Limits
+}
+
+func parsedProblem(archive string, problem StatisInfo, task database_client.Task, standardIO bool, limits judge.Limits) ParsedProblem {
+	return ParsedProblem{
+		Info:       Info{Archive: archive, ContestID: problem.Contest, StageID: problem.Stage, ProblemAlias: problem.Alias, ProblemID: problem.ID},
+		Name:       task.Name,
+		Link:       task.Link,
+		Path:       task.Path,
+		StandardIO: standardIO,
+		Limits:     limits,
+	}
+}
+
+func (c *SzkopulClient) Parse(info Info, db *sql.DB) (parsed []ParsedProblem, paths []string, err error) {
 	start := time.Now()
 
 	color.Cyan("Parse " + info.Hint())
@@ -215,8 +239,6 @@ func (c *SzkopulClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, pat
 	var retry []int
 	var avgPerformance util.Performance
 
-	parsed := 0
-
 	const numberOfWorkers = 50
 	index := 0
 
@@ -245,7 +267,9 @@ func (c *SzkopulClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, pat
 				}
 
 				var perf util.Performance
-				perf, err = c.parse(problemID, path, &mu)
+				var standardIO bool
+				var limits judge.Limits
+				standardIO, limits, perf, err = c.parse(problemID, path, &mu)
 				if err != nil && err.Error() == ErrorServiceUnavailable {
 					mu.Lock()
 					retry = append(retry, problemIndex)
@@ -269,7 +293,7 @@ func (c *SzkopulClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, pat
 					if err != nil {
 						color.Red(err.Error())
 					}
-					parsed++
+					parsed = append(parsed, parsedProblem(info.Archive, problems[problemIndex], task, standardIO, limits))
 				}
 				mu.Unlock()
 			}
@@ -286,7 +310,9 @@ func (c *SzkopulClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, pat
 		}
 
 		var perf util.Performance
-		perf, err = c.parse(problems[index].ID, paths[index], nil)
+		var standardIO bool
+		var limits judge.Limits
+		standardIO, limits, perf, err = c.parse(problems[index].ID, paths[index], nil)
 
 		avgPerformance.Fetching += perf.Fetching
 		avgPerformance.Parsing += perf.Parsing
@@ -304,15 +330,15 @@ func (c *SzkopulClient) Parse(info Info, db *sql.DB) (problems []StatisInfo, pat
 			if err != nil {
 				color.Red(err.Error())
 			}
-			parsed++
+			parsed = append(parsed, parsedProblem(info.Archive, problems[index], task, standardIO, limits))
 		}
 	}
-	color.Green("Successfully parsed %v problems.\n", parsed)
+	color.Green("Successfully parsed %v problems.\n", len(parsed))
 	avgPerformance.Fetching = util.AverageTime(avgPerformance.Fetching, len(problems))
 	avgPerformance.Parsing = util.AverageTime(avgPerformance.Parsing, len(problems))
 	fmt.Printf("Average: (%v)\n", avgPerformance.Parse())
 	fmt.Printf("Total: %s\n", time.Since(start).Round(time.Millisecond))
-	if parsed != len(problems) {
+	if len(parsed) != len(problems) {
 		err = errors.New(ErrorParsingProblemsFailed)
 	}
 	return
@@ -0,0 +1,220 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var archiveExtensions = []string{".zip", ".tgz", ".tar.gz", ".tar"}
+
+// IsArchive checks by the extension if the file is an archive which ExtractArchive can extract
+func IsArchive(path string) bool {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// safePath returns where the file from the archive is extracted, refusing paths which leave the destination.
+// The root of the archive (e.g. ./ written by tar czf x.tgz .) is skipped.
+func safePath(destination, name string) (target string, skip bool, err error) {
+	target = filepath.Join(destination, name)
+	rel, err := filepath.Rel(destination, target)
+	if err != nil || filepath.IsAbs(name) || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", false, fmt.Errorf("illegal file path in the archive: %v", name)
+	}
+	return target, rel == ".", nil
+}
+
+func extractFile(target string, mode os.FileMode, r io.Reader) (err error) {
+	if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return
+	}
+	_, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
+
+func extractZip(archive, destination string) (err error) {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		target, skip, err := safePath(destination, f.Name)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+		if f.FileInfo().IsDir() {
+			if err = os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = extractFile(target, f.Mode(), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return
+}
+
+func extractTar(archive, destination string) (err error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if !strings.HasSuffix(strings.ToLower(archive), ".tar") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target, skip, err := safePath(destination, header.Name)
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+		// links are skipped, they could point outside of the destination
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = extractFile(target, os.FileMode(header.Mode), tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ExtractArchive extracts a .zip, .tgz, .tar.gz or .tar archive into the destination folder
+func ExtractArchive(archive, destination string) error {
+	if err := os.MkdirAll(destination, os.ModePerm); err != nil {
+		return err
+	}
+	if strings.HasSuffix(strings.ToLower(archive), ".zip") {
+		return extractZip(archive, destination)
+	}
+	return extractTar(archive, destination)
+}
+
+// the folders of a package, a package with only one of them isn't wrapped in a package folder
+var packageContent = map[string]bool{"in": true, "out": true, "prog": true, "doc": true, "attachments": true}
+
+// the files marking the root folder of a sinol or Polygon package
+var packageRootFiles = []string{"config.yml", "package.xml"}
+
+// StripSingleDir moves the content of the only folder in dir (e.g. the package folder inside an archive) to dir,
+// unless that folder is a part of the package, like in/ of a package with only the tests
+func StripSingleDir(dir string) (err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return
+	}
+	single := filepath.Join(dir, entries[0].Name())
+	if packageContent[entries[0].Name()] && !containsAny(single, packageRootFiles) {
+		return
+	}
+	temp := dir + "-" + RandString(8)
+	if err = os.Rename(single, temp); err != nil {
+		return
+	}
+	if err = os.Remove(dir); err != nil {
+		return
+	}
+	return os.Rename(temp, dir)
+}
+
+func containsAny(dir string, names []string) bool {
+	for _, name := range names {
+		if FileExists(filepath.Join(dir, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateZip packs the folder into a .zip archive, inside a folder named like it (e.g. abc/in/abc1a.in)
+func CreateZip(dir, archive string) (err error) {
+	file, err := os.Create(archive)
+	if err != nil {
+		return
+	}
+	w := zip.NewWriter(file)
+	root := filepath.Dir(filepath.Clean(dir))
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		header.Method = zip.Deflate
+		f, err := w.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	})
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	w := zip.NewWriter(file)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTarGz(t *testing.T, path, prefix, content string) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	if prefix != "" {
+		_ = tw.WriteHeader(&tar.Header{Name: prefix, Typeflag: tar.TypeDir, Mode: 0755})
+	}
+	_ = tw.WriteHeader(&tar.Header{Name: prefix + "abc/", Typeflag: tar.TypeDir, Mode: 0755})
+	_ = tw.WriteHeader(&tar.Header{Name: prefix + "abc/in/abc1a.in", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})
+	_, _ = tw.Write([]byte(content))
+	_ = tw.WriteHeader(&tar.Header{Name: prefix + "abc/link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"})
+	tw.Close()
+	gz.Close()
+}
+
+func TestExtractArchive(t *testing.T) {
+	// tar czf package.tgz . writes the paths with ./ and the root folder first
+	for i, prefix := range []string{"", "./"} {
+		dir := t.TempDir()
+		archive := filepath.Join(dir, "package.tar.gz")
+		content := "1 2\n"
+		writeTarGz(t, archive, prefix, content)
+
+		destination := filepath.Join(dir, fmt.Sprint(i))
+		if err := ExtractArchive(archive, destination); err != nil {
+			t.Fatalf("Expect no error for %q, but found %v.", prefix, err)
+		}
+		if err := StripSingleDir(destination); err != nil {
+			t.Fatalf("Expect no error, but found %v.", err)
+		}
+		data, err := os.ReadFile(filepath.Join(destination, "in", "abc1a.in"))
+		if err != nil || string(data) != content {
+			t.Errorf("Expect %q, but found %q (%v).", content, data, err)
+		}
+		if _, err = os.Lstat(filepath.Join(destination, "link")); err == nil {
+			t.Errorf("Expect the symlink to be skipped.")
+		}
+	}
+}
+
+func TestStripSingleDir(t *testing.T) {
+	tests := []struct {
+		files  []string
+		expect string
+	}{
+		{[]string{"abc/in/abc1a.in"}, "in/abc1a.in"},
+		{[]string{"in/abc1a.in"}, "in/abc1a.in"},
+		{[]string{"prog/abc.cpp"}, "prog/abc.cpp"},
+		{[]string{"in/config.yml", "in/in/abc1a.in"}, "in/abc1a.in"},
+		{[]string{"abc/in/abc1a.in", "abc.txt"}, "abc/in/abc1a.in"},
+	}
+	for i, test := range tests {
+		dir := filepath.Join(t.TempDir(), fmt.Sprint(i))
+		for _, file := range test.files {
+			path := filepath.Join(dir, filepath.FromSlash(file))
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := StripSingleDir(dir); err != nil {
+			t.Fatalf("Expect no error, but found %v.", err)
+		}
+		if !FileExists(filepath.Join(dir, filepath.FromSlash(test.expect))) {
+			t.Errorf("Expect %v in the package of %v.", test.expect, test.files)
+		}
+	}
+}
+
+func TestExtractArchiveTraversal(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "evil.zip")
+	writeZip(t, archive, map[string]string{"../evil.txt": "evil"})
+	if err := ExtractArchive(archive, filepath.Join(dir, "0")); err == nil {
+		t.Errorf("Expect an error for a path leaving the destination.")
+	}
+	if FileExists(filepath.Join(dir, "evil.txt")) {
+		t.Errorf("Expect evil.txt not to be extracted.")
+	}
+}
+
+func TestCreateZip(t *testing.T) {
+	dir := t.TempDir()
+	packagePath := filepath.Join(dir, "abc")
+	if err := os.MkdirAll(filepath.Join(packagePath, "in"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(packagePath, "in", "abc1a.in"), []byte("1 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archive := filepath.Join(dir, "abc.zip")
+	if err := CreateZip(packagePath, archive); err != nil {
+		t.Fatal(err)
+	}
+	destination := filepath.Join(dir, "extracted")
+	if err := ExtractArchive(archive, destination); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(destination, "abc", "in", "abc1a.in"))
+	if err != nil || string(data) != "1 2\n" {
+		t.Errorf("Expect 1 2, but found %q (%v).", data, err)
+	}
+}
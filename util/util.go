@@ -81,6 +81,7 @@ const colorReset = "\033[0m"
 const colorRed = "\033[31m"
 const colorGreen = "\033[32m"
 const colorBlue = "\033[34m"
+const colorYellow = "\033[33m"
 
 func RedString(str string) string {
 	return fmt.Sprintf("%v%v%v", colorRed, str, colorReset)
@@ -92,6 +93,9 @@ func GreenString(str string) string {
 func BlueString(str string) string {
 	return fmt.Sprintf("%v%v%v", colorBlue, str, colorReset)
 }
+func YellowString(str string) string {
+	return fmt.Sprintf("%v%v%v", colorYellow, str, colorReset)
+}
 
 type Performance struct {
 	fetchingStart time.Time